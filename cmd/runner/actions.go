@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/Neoxs/gogh/internal/actions"
+	"github.com/spf13/cobra"
+)
+
+// pullOptions configures the actions pull command
+type pullOptions struct {
+	dir    string
+	remote string
+	mirror string
+	bundle string
+	update bool
+}
+
+// newActionsCommand creates the command group that manages the action cache
+func newActionsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "actions",
+		Short: "Manage the offline action cache",
+	}
+	cmd.AddCommand(newActionsPullCommand())
+	return cmd
+}
+
+// newActionsPullCommand creates the command that vendors the actions workflows use
+func newActionsPullCommand() *cobra.Command {
+	var options pullOptions
+
+	cmd := &cobra.Command{
+		Use:     "pull [workflow-file...]",
+		Aliases: []string{"vendor"},
+		Short:   "Fetch every action the workflows use into .gogh/actions-cache",
+		Long: "Scan workflows for uses: references, following local and remote reusable workflows and " +
+			"the steps of composite actions, and store each action repository in the action cache. " +
+			"Tags and branches are pinned to commits in " + actions.ActionLockFile + ", so pulling " +
+			"again fetches the same code until --update is given. Without files, every workflow in --dir is scanned.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return pullActions(options, args)
+		},
+	}
+
+	cmd.Flags().StringVar(&options.dir, "dir", filepath.Join(".github", "workflows"), "Directory containing the workflows")
+	cmd.Flags().StringVar(&options.remote, "remote", actions.DefaultRemoteURL, "Git URL to fetch repositories from; {repo} is replaced with owner/repo")
+	cmd.Flags().StringVar(&options.mirror, "mirror", "", "Directory of bare mirrors laid out as <owner>/<repo>.git, used instead of --remote")
+	cmd.Flags().StringVar(&options.bundle, "bundle", "", "Tarball (.tar or .tar.gz) with the layout of a --mirror directory, used instead of --remote")
+	cmd.Flags().BoolVar(&options.update, "update", false, "Resolve tags and branches again instead of using the locked commits")
+
+	return cmd
+}
+
+// pullActions fills the action cache and the lockfile from the chosen source
+func pullActions(options pullOptions, files []string) error {
+	if options.mirror != "" && options.bundle != "" {
+		return fmt.Errorf("--mirror and --bundle cannot be used together")
+	}
+
+	if len(files) == 0 {
+		var err error
+		if files, err = workflowFiles(options.dir); err != nil {
+			return err
+		}
+	}
+	projectDir := projectRoot(options.dir)
+
+	var source actions.ActionSource
+	switch {
+	case options.bundle != "":
+		bundleSource, cleanup, err := actions.NewBundleSource(options.bundle)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+		source = bundleSource
+	case options.mirror != "":
+		mirrorSource, err := actions.NewMirrorSource(options.mirror)
+		if err != nil {
+			return err
+		}
+		source = mirrorSource
+	default:
+		source = actions.NewRemoteSource(options.remote)
+	}
+
+	lock, err := actions.LoadActionLock(projectDir)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("📦 Pulling actions from %s\n", source)
+	fmt.Println()
+
+	puller := actions.NewActionPuller(actions.NewActionResolver(projectDir), source, lock, options.update)
+	failed := 0
+	for _, result := range puller.Pull(files) {
+		switch result.Status {
+		case actions.PullFetched:
+			fmt.Printf("⬇️  %s → %s\n", result.Ref, shortSHA(result.SHA))
+		case actions.PullCached:
+			fmt.Printf("✅ %s → %s (cached)\n", result.Ref, shortSHA(result.SHA))
+		case actions.PullBuiltin:
+			fmt.Printf("🔧 %s (built in)\n", result.Ref)
+		case actions.PullSkipped:
+			fmt.Printf("⏭️  %s (%v)\n", result.Ref, result.Err)
+		default:
+			failed++
+			fmt.Printf("❌ %s\n   • %v\n", result.Ref, result.Err)
+		}
+	}
+
+	if err := lock.Save(projectDir); err != nil {
+		return err
+	}
+	fmt.Println()
+	fmt.Printf("🔒 %d action repositories pinned in %s\n", len(lock.Actions), actions.ActionLockFile)
+
+	if failed > 0 {
+		return fmt.Errorf("%d action(s) could not be pulled", failed)
+	}
+	return nil
+}
+
+// shortSHA abbreviates a commit for display
+func shortSHA(sha string) string {
+	if len(sha) > 12 {
+		return sha[:12]
+	}
+	return sha
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/Neoxs/gogh/internal/environment"
+	"github.com/Neoxs/gogh/internal/executor"
+	"github.com/Neoxs/gogh/internal/workflow"
+)
+
+// discoveryOptions configures how workflows are selected when no file is given
+type discoveryOptions struct {
+	dir         string
+	changedFrom string
+}
+
+// runTriggeredWorkflows runs every workflow whose triggers match the event together,
+// under one display and one log root
+func runTriggeredWorkflows(discovery discoveryOptions, options executor.ExecutorOptions) error {
+	files, err := workflowFiles(discovery.dir)
+	if err != nil {
+		return err
+	}
+	projectDir := projectRoot(discovery.dir)
+
+	event, err := environment.LoadEvent(options.EventName, options.EventPath, projectDir)
+	if err != nil {
+		return err
+	}
+	options.Event = event
+	trigger := eventTrigger(event, projectDir)
+	description := addChangedFiles(&trigger, projectDir, discovery.changedFrom)
+
+	fmt.Printf("🔍 Detected project directory: %s\n", projectDir)
+	fmt.Printf("🎯 Event: %s\n", description)
+
+	// Broken workflow files are reported and fail the run, as on GitHub, without
+	// stopping the valid workflows from running
+	parser := workflow.NewParser()
+	var selected []executor.WorkflowFile
+	var invalid []string
+	for _, file := range files {
+		workflowDef, err := parser.ParseFile(file)
+		if err != nil {
+			fmt.Printf("❌ %s: %v\n", filepath.Base(file), err)
+			invalid = append(invalid, filepath.Base(file))
+			continue
+		}
+
+		result := workflowDef.On.Evaluate(trigger)
+		if !result.Fires {
+			fmt.Printf("⏭️  %s: %s\n", filepath.Base(file), strings.Join(result.Reasons, "; "))
+			continue
+		}
+		fmt.Printf("✅ %s: %s\n", filepath.Base(file), strings.Join(result.Reasons, "; "))
+		selected = append(selected, executor.WorkflowFile{Path: file, Definition: workflowDef})
+	}
+
+	invalidErr := func() error {
+		if len(invalid) == 0 {
+			return nil
+		}
+		return fmt.Errorf("invalid workflow files: %s", strings.Join(invalid, ", "))
+	}
+
+	if len(selected) == 0 {
+		fmt.Printf("No workflows in %s are triggered by %s\n", discovery.dir, trigger.Name)
+		return invalidErr()
+	}
+
+	group, err := executor.NewWorkflowGroup(selected, projectDir, options)
+	if err != nil {
+		return fmt.Errorf("failed to create workflow executor: %w", err)
+	}
+	if err := group.Execute(); err != nil {
+		return err
+	}
+	return invalidErr()
+}
+
+// eventTrigger describes an event payload in the terms trigger filters are evaluated on
+func eventTrigger(event *environment.Event, projectDir string) workflow.TriggerEvent {
+	trigger := workflow.TriggerEvent{Name: event.Name}
+	if action, ok := event.Value("action").(string); ok {
+		trigger.Action = action
+	}
+
+	switch event.Name {
+	case "pull_request", "pull_request_target":
+		if head, ok := event.Value("pull_request", "head", "ref").(string); ok && head != "" {
+			trigger.Ref = "refs/heads/" + head
+		}
+		if base, ok := event.Value("pull_request", "base", "ref").(string); ok {
+			trigger.BaseRef = base
+		}
+	case "release":
+		if tag, ok := event.Value("release", "tag_name").(string); ok && tag != "" {
+			trigger.Ref = "refs/tags/" + tag
+		}
+	default:
+		if ref, ok := event.Value("ref").(string); ok && ref != "" {
+			if !strings.HasPrefix(ref, "refs/") {
+				ref = "refs/heads/" + ref
+			}
+			trigger.Ref = ref
+		}
+	}
+
+	if trigger.Ref == "" {
+		trigger.Ref = environment.CurrentRef(projectDir)
+	}
+	return trigger
+}
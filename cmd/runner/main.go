@@ -1,12 +1,15 @@
 package main
 
 import (
+	"bufio"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 
 	"github.com/Neoxs/gogh/internal/executor"
+	"github.com/Neoxs/gogh/internal/secrets"
 	"github.com/Neoxs/gogh/internal/workflow"
 	"github.com/spf13/cobra"
 )
@@ -18,17 +21,38 @@ func main() {
 		Long:  "A tool to execute GitHub Actions workflows locally with Docker support",
 	}
 
+	var flags runFlags
+
 	var runCmd = &cobra.Command{
 		Use:   "run [workflow-file]",
-		Short: "Run a workflow file",
-		Args:  cobra.ExactArgs(1),
+		Short: "Run a workflow file, or every workflow an event triggers",
+		Long: "Run a workflow file. Without a file, every workflow in the workflows directory " +
+			"whose on: section matches --event is run together",
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			workflowFile := args[0]
-			return runWorkflow(workflowFile)
+			options, err := flags.executorOptions()
+			if err != nil {
+				return err
+			}
+
+			if len(args) == 0 {
+				if options.EventName == "" {
+					return fmt.Errorf("specify a workflow file, or --event to run every workflow the event triggers")
+				}
+				return runTriggeredWorkflows(flags.discovery, options)
+			}
+			return runWorkflow(args[0], options)
 		},
 	}
 
+	flags.register(runCmd)
+	runCmd.Flags().StringVar(&flags.options.EventName, "event", "", "Event that triggers the workflow, e.g. pull_request (default push)")
+
 	rootCmd.AddCommand(runCmd)
+	rootCmd.AddCommand(newEventCommand("push", "Run every workflow a push of the current branch triggers"))
+	rootCmd.AddCommand(newEventCommand("pull_request", "Run every workflow a pull request from the current branch triggers"))
+	rootCmd.AddCommand(newTriggersCommand())
+	rootCmd.AddCommand(newActionsCommand())
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
@@ -36,7 +60,92 @@ func main() {
 	}
 }
 
-func runWorkflow(workflowFile string) error {
+// runFlags holds the flags shared by run and the event commands
+type runFlags struct {
+	options                             executor.ExecutorOptions
+	matrixFilters, varFlags, inputFlags []string
+	secretFlags, secretFiles            []string
+	discovery                           discoveryOptions
+}
+
+// register adds the execution flags to a command
+func (f *runFlags) register(cmd *cobra.Command) {
+	cmd.Flags().IntVar(&f.options.MaxParallel, "max-parallel", 0, "Maximum number of jobs to run at once (0 = no limit)")
+	cmd.Flags().StringArrayVar(&f.matrixFilters, "matrix", nil, "Only run matrix combinations with this value, e.g. --matrix node=18 (repeatable)")
+	cmd.Flags().StringVar(&f.options.EventPath, "eventpath", "", "JSON file with the event payload exposed as github.event")
+	cmd.Flags().StringArrayVar(&f.inputFlags, "input", nil, "Set a workflow_dispatch input as name=value; implies --event workflow_dispatch (repeatable)")
+	cmd.Flags().StringArrayVar(&f.varFlags, "var", nil, "Set a configuration variable for the vars context as KEY=VALUE (repeatable)")
+	cmd.Flags().StringArrayVar(&f.secretFlags, "secret", nil, "Set a secret as KEY=VALUE, or KEY to read it from the environment or a prompt (repeatable)")
+	cmd.Flags().StringArrayVar(&f.secretFiles, "secret-file", nil, "Read secrets from a dotenv file, e.g. .secrets (repeatable)")
+	cmd.Flags().BoolVar(&f.options.LenientExpressions, "lenient-expressions", false, "Warn about expression errors instead of failing the step")
+	cmd.Flags().StringVar(&f.discovery.dir, "dir", filepath.Join(".github", "workflows"), "Directory searched for workflows when no file is given")
+	cmd.Flags().StringVar(&f.discovery.changedFrom, "changed-from", "", "Ref to diff against for path filters (default: the parent commit for pushes, the base branch for pull requests)")
+}
+
+// executorOptions converts the parsed flags into executor options
+func (f *runFlags) executorOptions() (executor.ExecutorOptions, error) {
+	options := f.options
+
+	matrixFilter, err := parseKeyValues(f.matrixFilters, "--matrix")
+	if err != nil {
+		return options, err
+	}
+	options.MatrixFilter = matrixFilter
+
+	vars, err := parseKeyValues(f.varFlags, "--var")
+	if err != nil {
+		return options, err
+	}
+	options.Vars = vars
+
+	inputs, err := parseKeyValues(f.inputFlags, "--input")
+	if err != nil {
+		return options, err
+	}
+	options.Inputs = inputs
+
+	// Inputs only exist for manual runs, so --input implies the workflow_dispatch event
+	if len(inputs) > 0 && options.EventName == "" {
+		options.EventName = "workflow_dispatch"
+	}
+	if isTerminal() {
+		options.PromptInput = promptInput
+	}
+
+	secretValues, err := resolveSecrets(f.secretFlags, f.secretFiles)
+	if err != nil {
+		return options, err
+	}
+	options.Secrets = secretValues
+
+	return options, nil
+}
+
+// newEventCommand creates a command that runs every workflow triggered by an event
+func newEventCommand(eventName, short string) *cobra.Command {
+	var flags runFlags
+
+	cmd := &cobra.Command{
+		Use:   eventName,
+		Short: short,
+		Long: fmt.Sprintf("Simulate a %s event and run every workflow in the workflows directory whose "+
+			"on: section matches it, including branch, tag and path filters", eventName),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			flags.options.EventName = eventName
+			options, err := flags.executorOptions()
+			if err != nil {
+				return err
+			}
+			return runTriggeredWorkflows(flags.discovery, options)
+		},
+	}
+
+	flags.register(cmd)
+	return cmd
+}
+
+func runWorkflow(workflowFile string, options executor.ExecutorOptions) error {
 	// Parse the workflow
 	parser := workflow.NewParser()
 	workflowDef, err := parser.ParseFile(workflowFile)
@@ -55,7 +164,7 @@ func runWorkflow(workflowFile string) error {
 	fmt.Printf("🔍 Workflow file: %s\n", workflowFile)
 
 	// Create executor with logging and display (now returns error)
-	executor, err := executor.NewWorkflowExecutor(workflowDef, projectDir)
+	executor, err := executor.NewWorkflowExecutor(workflowDef, projectDir, options)
 	if err != nil {
 		return fmt.Errorf("failed to create workflow executor: %w", err)
 	}
@@ -91,3 +200,104 @@ func getProjectDirectory(workflowFile string) (string, error) {
 	// This allows for workflows stored elsewhere
 	return workflowDir, nil
 }
+
+// parseKeyValues converts repeated KEY=VALUE flag values into a map
+func parseKeyValues(values []string, flagName string) (map[string]string, error) {
+	result := make(map[string]string)
+	for _, value := range values {
+		key, val, found := strings.Cut(value, "=")
+		if !found || key == "" {
+			return nil, fmt.Errorf("invalid %s value %q: expected KEY=VALUE", flagName, value)
+		}
+		result[key] = val
+	}
+	return result, nil
+}
+
+// resolveSecrets builds the secrets context. Secret files are read in order, then each
+// --secret overrides them: KEY=VALUE sets the value directly, while a bare KEY is read
+// from the host environment or, failing that, prompted for on the terminal.
+func resolveSecrets(flagValues, files []string) (map[string]string, error) {
+	result := make(map[string]string)
+	for _, file := range files {
+		values, err := secrets.LoadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		for key, value := range values {
+			result[key] = value
+		}
+	}
+
+	for _, flagValue := range flagValues {
+		key, value, found := strings.Cut(flagValue, "=")
+		if key == "" {
+			return nil, fmt.Errorf("invalid --secret value %q: expected KEY=VALUE or KEY", flagValue)
+		}
+		if !found {
+			var err error
+			if value, err = lookupSecret(key); err != nil {
+				return nil, err
+			}
+		}
+		result[key] = value
+	}
+
+	return result, nil
+}
+
+// lookupSecret reads a secret named on the command line without a value
+func lookupSecret(key string) (string, error) {
+	if value, exists := os.LookupEnv(key); exists {
+		return value, nil
+	}
+
+	if !isTerminal() {
+		return "", fmt.Errorf("secret %s is not set in the environment and there is no terminal to prompt on", key)
+	}
+
+	fmt.Printf("🔑 Value for secret %s: ", key)
+
+	// Hide the typed value; if stty is unavailable the prompt still works, just echoed
+	setEcho := func(mode string) {
+		stty := exec.Command("stty", mode)
+		stty.Stdin = os.Stdin
+		stty.Run()
+	}
+	setEcho("-echo")
+	value, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	setEcho("echo")
+	fmt.Println()
+
+	if err != nil && value == "" {
+		return "", fmt.Errorf("failed to read secret %s: %w", key, err)
+	}
+	return strings.TrimRight(value, "\r\n"), nil
+}
+
+// promptInput asks for a required workflow_dispatch input on the terminal
+func promptInput(name string, input workflow.InputDefinition) (string, error) {
+	label := name
+	if input.Description != "" {
+		label = fmt.Sprintf("%s (%s)", name, input.Description)
+	}
+	switch {
+	case len(input.Options) > 0:
+		label += fmt.Sprintf(" [%s]", strings.Join(input.Options, "/"))
+	case input.Type == workflow.InputTypeBoolean:
+		label += " [true/false]"
+	}
+
+	fmt.Printf("📝 Input %s: ", label)
+	value, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && value == "" {
+		return "", err
+	}
+	return strings.TrimRight(value, "\r\n"), nil
+}
+
+// isTerminal reports whether standard input is interactive, so it is safe to prompt
+func isTerminal() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Neoxs/gogh/internal/environment"
+	"github.com/Neoxs/gogh/internal/workflow"
+	"github.com/spf13/cobra"
+)
+
+// triggerOptions configures the triggers command
+type triggerOptions struct {
+	event       string
+	ref         string
+	base        string
+	action      string
+	changedFrom string
+	dir         string
+}
+
+// newTriggersCommand creates the command that reports which workflows an event would start
+func newTriggersCommand() *cobra.Command {
+	var options triggerOptions
+
+	cmd := &cobra.Command{
+		Use:   "triggers",
+		Short: "Show which workflows an event would trigger and why",
+		Long: "Evaluate the on: section of every workflow against a simulated event, " +
+			"including branch, tag, path and activity type filters, using local git state",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return reportTriggers(options)
+		},
+	}
+
+	cmd.Flags().StringVar(&options.event, "event", environment.DefaultEventName, "Event to simulate, e.g. push, pull_request or schedule")
+	cmd.Flags().StringVar(&options.ref, "ref", "", "Ref pushed or the pull request head, e.g. refs/heads/feature/x or refs/tags/v1.0 (default: current branch)")
+	cmd.Flags().StringVar(&options.base, "base", "", "Target branch of a pull request (default: the repository's default branch)")
+	cmd.Flags().StringVar(&options.action, "type", "", "Activity type, e.g. opened or published (default: opened for pull requests, published for releases)")
+	cmd.Flags().StringVar(&options.changedFrom, "changed-from", "", "Ref to diff against for path filters (default: the parent commit for pushes, the base branch for pull requests)")
+	cmd.Flags().StringVar(&options.dir, "dir", filepath.Join(".github", "workflows"), "Directory containing the workflows")
+
+	return cmd
+}
+
+// reportTriggers evaluates every workflow against the event and prints the outcome
+func reportTriggers(options triggerOptions) error {
+	files, err := workflowFiles(options.dir)
+	if err != nil {
+		return err
+	}
+
+	event, source := triggerEvent(options)
+	fmt.Printf("🎯 Event: %s\n", source)
+	fmt.Println()
+
+	parser := workflow.NewParser()
+	for _, file := range files {
+		name := filepath.Base(file)
+
+		workflowDef, err := parser.ParseFile(file)
+		if err != nil {
+			fmt.Printf("❌ %s\n   • %v\n", name, err)
+			continue
+		}
+
+		result := workflowDef.On.Evaluate(event)
+		icon := "⏭️"
+		if result.Fires {
+			icon = "✅"
+		}
+		fmt.Printf("%s %s (%s)\n", icon, name, workflowDef.Name)
+		for _, reason := range result.Reasons {
+			fmt.Printf("   • %s\n", reason)
+		}
+	}
+
+	return nil
+}
+
+// triggerEvent builds the simulated event from the flags and local git state,
+// along with a one-line description of it
+func triggerEvent(options triggerOptions) (workflow.TriggerEvent, string) {
+	projectDir := projectRoot(options.dir)
+
+	event := workflow.TriggerEvent{
+		Name:   options.event,
+		Ref:    options.ref,
+		Action: options.action,
+	}
+	if event.Ref == "" {
+		event.Ref = environment.CurrentRef(projectDir)
+	} else if !strings.HasPrefix(event.Ref, "refs/") {
+		event.Ref = "refs/heads/" + event.Ref
+	}
+
+	switch event.Name {
+	case "pull_request", "pull_request_target":
+		event.BaseRef = strings.TrimPrefix(options.base, "refs/heads/")
+		if event.BaseRef == "" {
+			event.BaseRef = environment.DefaultBranch(projectDir)
+		}
+		if event.Action == "" {
+			event.Action = "opened"
+		}
+	case "release":
+		if event.Action == "" {
+			event.Action = "published"
+		}
+	}
+
+	description := addChangedFiles(&event, projectDir, options.changedFrom)
+	return event, description
+}
+
+// addChangedFiles lists the files the event changed, for path filters, and returns a
+// one-line description of the event. Without changedFrom, pushes are compared with the
+// parent commit and pull requests with their base branch.
+func addChangedFiles(event *workflow.TriggerEvent, projectDir, changedFrom string) string {
+	description := fmt.Sprintf("%s to %s", event.Name, event.Ref)
+
+	switch event.Name {
+	case "pull_request", "pull_request_target":
+		if changedFrom == "" {
+			changedFrom = event.BaseRef
+		}
+		description = fmt.Sprintf("%s (%s) from %s into %s", event.Name, event.Action, strings.TrimPrefix(event.Ref, "refs/heads/"), event.BaseRef)
+	case "push":
+		if changedFrom == "" {
+			changedFrom = "HEAD~1"
+		}
+	default:
+		description = event.Name
+		if event.Action != "" {
+			description += fmt.Sprintf(" (%s)", event.Action)
+		}
+	}
+
+	if changedFrom != "" {
+		files, err := environment.ChangedFiles(projectDir, changedFrom)
+		if err != nil {
+			description += fmt.Sprintf("; path filters not evaluated: %v", err)
+		} else {
+			event.ChangedFiles = files
+			description += fmt.Sprintf(", %d file(s) changed since %s", len(files), changedFrom)
+		}
+	}
+
+	return description
+}
+
+// workflowFiles lists the .yml and .yaml files in a workflows directory
+func workflowFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workflows directory: %w", err)
+	}
+
+	var files []string
+	for _, entry := range entries {
+		extension := filepath.Ext(entry.Name())
+		if !entry.IsDir() && (extension == ".yml" || extension == ".yaml") {
+			files = append(files, filepath.Join(dir, entry.Name()))
+		}
+	}
+	sort.Strings(files)
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no workflows found in %s", dir)
+	}
+	return files, nil
+}
+
+// projectRoot returns the project a workflows directory belongs to
+func projectRoot(workflowsDir string) string {
+	absDir, err := filepath.Abs(workflowsDir)
+	if err != nil {
+		return "."
+	}
+	if filepath.Base(absDir) == "workflows" && filepath.Base(filepath.Dir(absDir)) == ".github" {
+		return filepath.Dir(filepath.Dir(absDir))
+	}
+	return absDir
+}
@@ -0,0 +1,70 @@
+package container
+
+import (
+	"fmt"
+	"os/exec"
+	"time"
+
+	"github.com/Neoxs/gogh/internal/logging"
+)
+
+// eventDir holds the event payload written for GITHUB_EVENT_PATH
+const eventDir = "/github/workflow"
+
+// ActionContainer describes the container of a Docker action
+type ActionContainer struct {
+	Image      string
+	Entrypoint string // Replaces the image's ENTRYPOINT when set
+	Args       []string
+	Env        map[string]string
+}
+
+// RunActionContainer runs a Docker action next to the job container. It joins the job
+// container's network and mounts its volumes, so it sees the services the job sees, the
+// workspace, the event payload and the step's file-command files at the same paths.
+func (jr *JobRunner) RunActionContainer(stepName string, action ActionContainer, jobLogger *logging.JobLogger) (*StepResult, error) {
+	if !jr.isRunning {
+		return nil, fmt.Errorf("container not running")
+	}
+
+	result := &StepResult{
+		StepName:  stepName,
+		Command:   action.Image,
+		StartTime: time.Now(),
+	}
+
+	args := []string{
+		"run", "--rm",
+		"--network", "container:" + jr.containerID,
+		"--volumes-from", jr.containerID,
+		"-w", jr.workspaceDir,
+	}
+	if action.Entrypoint != "" {
+		args = append(args, "--entrypoint", action.Entrypoint)
+	}
+	for key, value := range action.Env {
+		args = append(args, "-e", fmt.Sprintf("%s=%s", key, value))
+	}
+	args = append(args, action.Image)
+	args = append(args, action.Args...)
+
+	return jr.runStreaming(exec.Command("docker", args...), result, jobLogger)
+}
+
+// ImageExists reports whether an image is available locally
+func ImageExists(image string) bool {
+	return exec.Command("docker", "image", "inspect", image).Run() == nil
+}
+
+// BuildImage builds an image for the job from a Dockerfile, streaming the build output to the job log
+func (jr *JobRunner) BuildImage(contextDir, dockerfile, tag string, jobLogger *logging.JobLogger) error {
+	cmd := exec.Command("docker", "build", "-t", tag, "-f", dockerfile, contextDir)
+	result, err := jr.runStreaming(cmd, &StepResult{StepName: "build", StartTime: time.Now()}, jobLogger)
+	if err != nil {
+		return fmt.Errorf("failed to build %s: %w", tag, err)
+	}
+	if !result.Success {
+		return fmt.Errorf("failed to build %s: docker build exited with code %d", tag, result.ExitCode)
+	}
+	return nil
+}
@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"io"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -71,6 +73,9 @@ func (jr *JobRunner) Start() error {
 		"-d",                                                       // detached mode
 		"--rm",                                                     // auto-remove when stopped
 		"-v", fmt.Sprintf("%s:%s", jr.projectDir, jr.workspaceDir), // mount project
+		"-v", fmt.Sprintf("%s:%s", externalsVolume, externalsDir), // node runtimes for actions
+		"-v", fileCommandsDir, // file-command files and the event payload are volumes
+		"-v", eventDir, // so Docker action containers see them too
 		"-w", jr.workspaceDir, // set working directory
 		jr.image,
 		"sleep", "3600", // keep container alive for 1 hour
@@ -88,8 +93,9 @@ func (jr *JobRunner) Start() error {
 	return nil
 }
 
-// RunStep executes a single step command inside the container with logging and environment
-func (jr *JobRunner) RunStep(stepName, command string, env map[string]string, jobLogger *logging.JobLogger) (*StepResult, error) {
+// RunStep executes a single step command inside the container with logging and environment.
+// workingDir is relative to the workspace unless absolute; empty means the workspace itself.
+func (jr *JobRunner) RunStep(stepName, workingDir, command string, env map[string]string, jobLogger *logging.JobLogger) (*StepResult, error) {
 	if !jr.isRunning {
 		return nil, fmt.Errorf("container not running")
 	}
@@ -103,6 +109,13 @@ func (jr *JobRunner) RunStep(stepName, command string, env map[string]string, jo
 	// Build Docker exec command with environment variables
 	args := []string{"exec"}
 
+	if workingDir != "" {
+		if !path.IsAbs(workingDir) {
+			workingDir = path.Join(jr.workspaceDir, workingDir)
+		}
+		args = append(args, "-w", workingDir)
+	}
+
 	// Add environment variables as -e flags
 	for key, value := range env {
 		args = append(args, "-e", fmt.Sprintf("%s=%s", key, value))
@@ -112,7 +125,11 @@ func (jr *JobRunner) RunStep(stepName, command string, env map[string]string, jo
 	args = append(args, jr.containerID, "bash", "-c", command)
 
 	cmd := exec.Command("docker", args...)
+	return jr.runStreaming(cmd, result, jobLogger)
+}
 
+// runStreaming runs a docker command, streaming its output to the job log, and records its exit code
+func (jr *JobRunner) runStreaming(cmd *exec.Cmd, result *StepResult, jobLogger *logging.JobLogger) (*StepResult, error) {
 	// Capture both stdout and stderr
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
@@ -133,8 +150,19 @@ func (jr *JobRunner) RunStep(stepName, command string, env map[string]string, jo
 	}
 
 	// Stream output directly to logger
-	go jr.streamOutputToLogger(stdout, jobLogger)
-	go jr.streamOutputToLogger(stderr, jobLogger)
+	var streams sync.WaitGroup
+	streams.Add(2)
+	go func() {
+		defer streams.Done()
+		jr.streamOutputToLogger(stdout, jobLogger)
+	}()
+	go func() {
+		defer streams.Done()
+		jr.streamOutputToLogger(stderr, jobLogger)
+	}()
+
+	// All output must be read before Wait closes the pipes
+	streams.Wait()
 
 	// Wait for command to complete
 	err = cmd.Wait()
@@ -164,13 +192,13 @@ func (jr *JobRunner) RunStep(stepName, command string, env map[string]string, jo
 
 // RunStepInEnvironment is a convenience method that runs a command with environment setup
 func (jr *JobRunner) RunStepInEnvironment(stepName, command string, env map[string]string, jobLogger *logging.JobLogger) (*StepResult, error) {
-	// Log environment variables (excluding sensitive ones)
+	// Log environment variables; the job logger masks secret values
 	jr.logEnvironmentVariables(env, jobLogger)
 
-	return jr.RunStep(stepName, command, env, jobLogger)
+	return jr.RunStep(stepName, "", command, env, jobLogger)
 }
 
-// logEnvironmentVariables logs environment setup (filtering sensitive data)
+// logEnvironmentVariables logs environment setup
 func (jr *JobRunner) logEnvironmentVariables(env map[string]string, jobLogger *logging.JobLogger) {
 	if len(env) == 0 {
 		return
@@ -178,28 +206,8 @@ func (jr *JobRunner) logEnvironmentVariables(env map[string]string, jobLogger *l
 
 	jobLogger.LogStepOutput("Environment variables:")
 	for key, value := range env {
-		// Filter out potentially sensitive variables
-		if jr.isSensitiveVar(key) {
-			jobLogger.LogStepOutput(fmt.Sprintf("  %s=***", key))
-		} else {
-			jobLogger.LogStepOutput(fmt.Sprintf("  %s=%s", key, value))
-		}
-	}
-}
-
-// isSensitiveVar checks if a variable name suggests sensitive content
-func (jr *JobRunner) isSensitiveVar(key string) bool {
-	sensitivePatterns := []string{
-		"TOKEN", "SECRET", "KEY", "PASSWORD", "PASS", "AUTH", "CREDENTIAL",
-	}
-
-	upperKey := strings.ToUpper(key)
-	for _, pattern := range sensitivePatterns {
-		if strings.Contains(upperKey, pattern) {
-			return true
-		}
+		jobLogger.LogStepOutput(fmt.Sprintf("  %s=%s", key, value))
 	}
-	return false
 }
 
 // streamOutputToLogger reads from pipe and writes directly to job logger
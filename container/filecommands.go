@@ -0,0 +1,121 @@
+package container
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os/exec"
+	"path"
+	"strings"
+)
+
+// fileCommandsDir is where per-step file-command files live inside the container
+const fileCommandsDir = "/tmp/_runner_file_commands"
+
+// defaultSystemPath is used when the container's PATH cannot be read
+const defaultSystemPath = "/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"
+
+// FileCommands holds the paths of the file-command files created for a single step
+type FileCommands struct {
+	Env         string // GITHUB_ENV
+	Output      string // GITHUB_OUTPUT
+	Path        string // GITHUB_PATH
+	StepSummary string // GITHUB_STEP_SUMMARY
+	State       string // GITHUB_STATE, read by JavaScript actions for their post step
+}
+
+// Environment returns the variables that point a step at its file-command files
+func (fc *FileCommands) Environment() map[string]string {
+	return map[string]string{
+		"GITHUB_ENV":          fc.Env,
+		"GITHUB_OUTPUT":       fc.Output,
+		"GITHUB_PATH":         fc.Path,
+		"GITHUB_STEP_SUMMARY": fc.StepSummary,
+		"GITHUB_STATE":        fc.State,
+	}
+}
+
+// PrepareFileCommands creates empty file-command files for the next step inside the container
+func (jr *JobRunner) PrepareFileCommands() (*FileCommands, error) {
+	if !jr.isRunning {
+		return nil, fmt.Errorf("container not running")
+	}
+
+	suffix, err := randomSuffix()
+	if err != nil {
+		return nil, fmt.Errorf("failed to name file command files: %w", err)
+	}
+
+	files := &FileCommands{
+		Env:         path.Join(fileCommandsDir, "set_env_"+suffix),
+		Output:      path.Join(fileCommandsDir, "set_output_"+suffix),
+		Path:        path.Join(fileCommandsDir, "add_path_"+suffix),
+		StepSummary: path.Join(fileCommandsDir, "step_summary_"+suffix),
+		State:       path.Join(fileCommandsDir, "save_state_"+suffix),
+	}
+
+	script := fmt.Sprintf("mkdir -p %s && : > %s && : > %s && : > %s && : > %s && : > %s",
+		fileCommandsDir, files.Env, files.Output, files.Path, files.StepSummary, files.State)
+	if output, err := jr.execInContainer("sh", "-c", script); err != nil {
+		return nil, fmt.Errorf("failed to create file command files: %v\nOutput: %s", err, string(output))
+	}
+
+	return files, nil
+}
+
+// ReadFile returns the contents of a file inside the container
+func (jr *JobRunner) ReadFile(filePath string) (string, error) {
+	if !jr.isRunning {
+		return "", fmt.Errorf("container not running")
+	}
+
+	output, err := jr.execInContainer("cat", filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %v", filePath, err)
+	}
+	return string(output), nil
+}
+
+// WriteFile creates or replaces a file inside the container, creating its directory
+func (jr *JobRunner) WriteFile(filePath string, content []byte) error {
+	if !jr.isRunning {
+		return fmt.Errorf("container not running")
+	}
+
+	script := fmt.Sprintf("mkdir -p %s && cat > %s", path.Dir(filePath), filePath)
+	cmd := exec.Command("docker", "exec", "-i", jr.containerID, "sh", "-c", script)
+	cmd.Stdin = bytes.NewReader(content)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to write %s: %v\nOutput: %s", filePath, err, string(output))
+	}
+	return nil
+}
+
+// SystemPath returns the container's default PATH, which GITHUB_PATH entries are prepended to
+func (jr *JobRunner) SystemPath() string {
+	if !jr.isRunning {
+		return defaultSystemPath
+	}
+
+	output, err := jr.execInContainer("printenv", "PATH")
+	if err != nil || strings.TrimSpace(string(output)) == "" {
+		return defaultSystemPath
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// execInContainer runs a command in the container and returns its output
+func (jr *JobRunner) execInContainer(command ...string) ([]byte, error) {
+	args := append([]string{"exec", jr.containerID}, command...)
+	return exec.Command("docker", args...).Output()
+}
+
+// randomSuffix returns a unique suffix so every step gets fresh files
+func randomSuffix() (string, error) {
+	buffer := make([]byte, 8)
+	if _, err := rand.Read(buffer); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buffer), nil
+}
@@ -0,0 +1,51 @@
+package container
+
+import (
+	"fmt"
+	"os/exec"
+	"path"
+)
+
+// Node runtimes for JavaScript actions live in a Docker volume that every job container
+// mounts, like the externals directory of the GitHub runner. A runtime is copied into the
+// volume from its official image the first time an action needs it, so later runs work offline.
+const (
+	externalsVolume = "gogh-externals"
+	externalsDir    = "/__e"
+)
+
+// nodeRuntimeImages are the images the node binary of each runs.using is copied from
+var nodeRuntimeImages = map[string]string{
+	"node16": "node:16-bullseye-slim",
+	"node20": "node:20-bookworm-slim",
+	"node24": "node:24-bookworm-slim",
+}
+
+// NodeRuntime returns the path of the node binary for a runs.using value such as node20
+// inside the job container, installing it into the externals volume if needed
+func (jr *JobRunner) NodeRuntime(using string) (string, error) {
+	if !jr.isRunning {
+		return "", fmt.Errorf("container not running")
+	}
+
+	image, supported := nodeRuntimeImages[using]
+	if !supported {
+		return "", fmt.Errorf("unsupported node runtime '%s'", using)
+	}
+
+	runtimeDir := path.Join(externalsDir, using)
+	node := path.Join(runtimeDir, "bin", "node")
+	if _, err := jr.execInContainer("test", "-x", node); err == nil {
+		return node, nil
+	}
+
+	// Copy under a temporary name so jobs installing the same runtime never see a partial binary
+	script := fmt.Sprintf("mkdir -p %[1]s/bin && cp /usr/local/bin/node %[1]s/bin/node.$$ && mv %[1]s/bin/node.$$ %[1]s/bin/node", runtimeDir)
+	cmd := exec.Command("docker", "run", "--rm",
+		"-v", fmt.Sprintf("%s:%s", externalsVolume, externalsDir),
+		"--entrypoint", "sh", image, "-c", script)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("failed to install the %s runtime from %s (pull the image while online): %v\nOutput: %s", using, image, err, string(output))
+	}
+	return node, nil
+}
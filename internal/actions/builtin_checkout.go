@@ -10,14 +10,39 @@ import (
 // CheckoutAction implements actions/checkout functionality
 type CheckoutAction struct{}
 
+// checkoutMetadata declares the inputs of actions/checkout; the project is already
+// mounted as the workspace, so most of them have no effect locally
+var checkoutMetadata = &ActionMetadata{
+	Name:        "Checkout",
+	Description: "Checkout a Git repository at a particular version",
+	Inputs: map[string]ActionInput{
+		"repository":          {Description: "Repository name with owner", Default: defaultValue("${{ github.repository }}")},
+		"ref":                 {Description: "The branch, tag or SHA to checkout"},
+		"token":               {Description: "Personal access token used to fetch the repository", Default: defaultValue("${{ github.token }}")},
+		"ssh-key":             {Description: "SSH key used to fetch the repository"},
+		"persist-credentials": {Description: "Whether to configure the token or SSH key with the local git config", Default: defaultValue("true")},
+		"path":                {Description: "Relative path under $GITHUB_WORKSPACE to place the repository"},
+		"clean":               {Description: "Whether to execute git clean -ffdx && git reset --hard HEAD before fetching", Default: defaultValue("true")},
+		"fetch-depth":         {Description: "Number of commits to fetch. 0 indicates all history", Default: defaultValue("1")},
+		"fetch-tags":          {Description: "Whether to fetch tags, even if fetch-depth > 0", Default: defaultValue("false")},
+		"lfs":                 {Description: "Whether to download Git-LFS files", Default: defaultValue("false")},
+		"submodules":          {Description: "Whether to checkout submodules: true, false or recursive", Default: defaultValue("false")},
+		"sparse-checkout":     {Description: "Patterns to use for a sparse checkout"},
+	},
+	Outputs: map[string]ActionOutput{
+		"ref":    {Description: "The branch, tag or SHA that was checked out"},
+		"commit": {Description: "The commit SHA that was checked out"},
+		"path":   {Description: "Where the repository was checked out"},
+	},
+	Runs: ActionRuns{Using: "builtin"},
+}
+
 func (ca *CheckoutAction) GetName() string {
 	return "actions/checkout"
 }
 
-func (ca *CheckoutAction) ValidateInputs(inputs map[string]string) error {
-	// For now, we'll be permissive and accept any inputs
-	// TODO: Please update later
-	return nil
+func (ca *CheckoutAction) GetMetadata() *ActionMetadata {
+	return checkoutMetadata
 }
 
 func (ca *CheckoutAction) Execute(ctx *ActionContext, jobLogger *logging.JobLogger) (*ActionResult, error) {
@@ -11,18 +11,36 @@ import (
 // SetupNodeAction implements actions/setup-node functionality
 type SetupNodeAction struct{}
 
+// setupNodeMetadata declares the inputs of actions/setup-node; Node.js is installed
+// from NodeSource, so only node-version changes what is installed locally
+var setupNodeMetadata = &ActionMetadata{
+	Name:        "Setup Node.js environment",
+	Description: "Setup a Node.js environment and add it to the PATH",
+	Inputs: map[string]ActionInput{
+		"node-version":          {Description: "Version spec of the version to use, e.g. 20 or 18.x (default: 18)"},
+		"node-version-file":     {Description: "File containing the version spec, e.g. .nvmrc"},
+		"architecture":          {Description: "Target architecture for Node to use"},
+		"check-latest":          {Description: "Check for the latest available version that satisfies the spec", Default: defaultValue("false")},
+		"registry-url":          {Description: "Registry to set up for auth"},
+		"scope":                 {Description: "Scope for authenticating against scoped registries"},
+		"token":                 {Description: "Used to pull node distributions from node-versions", Default: defaultValue("${{ github.token }}")},
+		"cache":                 {Description: "Package manager for caching: npm, yarn or pnpm"},
+		"cache-dependency-path": {Description: "Path to a dependency file used for caching"},
+		"always-auth":           {Description: "Set always-auth in npmrc", Default: defaultValue("false"), DeprecationMessage: "always-auth is deprecated and has no effect"},
+	},
+	Outputs: map[string]ActionOutput{
+		"node-version": {Description: "The installed node version"},
+		"npm-version":  {Description: "The installed npm version"},
+	},
+	Runs: ActionRuns{Using: "builtin"},
+}
+
 func (sna *SetupNodeAction) GetName() string {
 	return "actions/setup-node"
 }
 
-func (sna *SetupNodeAction) ValidateInputs(inputs map[string]string) error {
-	// node-version is the most important input
-	if nodeVersion, exists := inputs["node-version"]; exists {
-		if nodeVersion == "" {
-			return fmt.Errorf("node-version cannot be empty")
-		}
-	}
-	return nil
+func (sna *SetupNodeAction) GetMetadata() *ActionMetadata {
+	return setupNodeMetadata
 }
 
 func (sna *SetupNodeAction) Execute(ctx *ActionContext, jobLogger *logging.JobLogger) (*ActionResult, error) {
@@ -0,0 +1,42 @@
+package actions
+
+import (
+	"fmt"
+
+	"github.com/Neoxs/gogh/internal/logging"
+)
+
+// CompositeStepRunner runs the steps of a composite action inside the job with the given
+// inputs and returns the action's outputs. Steps need the executor's expressions, file
+// commands and display, so the executor provides it through ActionContext.
+type CompositeStepRunner func(action *CompositeAction, inputs map[string]string, jobLogger *logging.JobLogger) (map[string]string, error)
+
+// CompositeAction runs an action whose action.yml has runs.using: composite
+type CompositeAction struct {
+	Ref      string          // The uses: reference
+	Dir      string          // Action directory on the host
+	Path     string          // Action directory inside the job container, exposed as GITHUB_ACTION_PATH
+	Metadata *ActionMetadata // Parsed action.yml
+}
+
+func (ca *CompositeAction) GetName() string {
+	return ca.Ref
+}
+
+func (ca *CompositeAction) GetMetadata() *ActionMetadata {
+	return ca.Metadata
+}
+
+func (ca *CompositeAction) Execute(ctx *ActionContext, jobLogger *logging.JobLogger) (*ActionResult, error) {
+	if ctx.RunSteps == nil {
+		return nil, fmt.Errorf("composite action %s can only run inside a job", ca.Ref)
+	}
+
+	jobLogger.LogStepOutput(fmt.Sprintf("Running composite action %s (%d steps)", ca.Ref, len(ca.Metadata.Runs.Steps)))
+
+	outputs, err := ctx.RunSteps(ca, ctx.Inputs, jobLogger)
+	if err != nil {
+		return &ActionResult{Success: false, Outputs: outputs, Error: err}, err
+	}
+	return &ActionResult{Success: true, Outputs: outputs}, nil
+}
@@ -0,0 +1,323 @@
+package actions
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Neoxs/gogh/container"
+	"github.com/Neoxs/gogh/internal/logging"
+)
+
+// dockerImagePrefix marks an image reference, both in uses: and in runs.image
+const dockerImagePrefix = "docker://"
+
+// DockerAction runs an action in its own container: either an action whose action.yml has
+// runs.using: docker, or a step with uses: docker://image. The container shares the job
+// container's network and volumes; inputs are passed as INPUT_* variables.
+type DockerAction struct {
+	Ref      string          // The uses: reference
+	Dir      string          // Action directory on the host; empty for docker:// steps
+	Metadata *ActionMetadata // Parsed action.yml, or the inputs a docker:// step accepts
+
+	builtImage string // Image built from the Dockerfile, reused by the pre and post phases
+}
+
+// IsDockerImageRef reports whether uses: runs an image directly, e.g. docker://alpine:3.19
+func IsDockerImageRef(actionRef string) bool {
+	return strings.HasPrefix(actionRef, dockerImagePrefix)
+}
+
+// newDockerAction checks that a Docker action names an image or a Dockerfile that exists
+func newDockerAction(actionRef, dir string, metadata *ActionMetadata) (*DockerAction, error) {
+	image := metadata.Runs.Image
+	if image == "" {
+		return nil, fmt.Errorf("action %s: runs.image is required for docker actions", actionRef)
+	}
+	if !strings.HasPrefix(image, dockerImagePrefix) {
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(image))); err != nil {
+			return nil, fmt.Errorf("action %s: Dockerfile %s not found in %s", actionRef, image, dir)
+		}
+	}
+
+	return &DockerAction{Ref: actionRef, Dir: dir, Metadata: metadata}, nil
+}
+
+// newDockerImageAction creates the action for a uses: docker://image step, which takes
+// its command line from with.args and with.entrypoint
+func newDockerImageAction(actionRef string) *DockerAction {
+	return &DockerAction{
+		Ref: actionRef,
+		Metadata: &ActionMetadata{
+			Name: actionRef,
+			Inputs: map[string]ActionInput{
+				"args":       {Description: "Arguments passed to the container"},
+				"entrypoint": {Description: "Replaces the image's ENTRYPOINT"},
+			},
+			Runs:      ActionRuns{Using: "docker", Image: actionRef},
+			anyInputs: true,
+		},
+	}
+}
+
+func (da *DockerAction) GetName() string {
+	return da.Ref
+}
+
+func (da *DockerAction) GetMetadata() *ActionMetadata {
+	return da.Metadata
+}
+
+// Execute runs the pre-entrypoint, when its pre-if allows it, followed by the main entrypoint.
+// Both run at the start of the step rather than at the start of the job.
+func (da *DockerAction) Execute(ctx *ActionContext, jobLogger *logging.JobLogger) (*ActionResult, error) {
+	runs := da.Metadata.Runs
+
+	if runs.PreEntrypoint != "" {
+		if run, err := da.shouldRun(ctx, runs.PreIf); err != nil {
+			return &ActionResult{Success: false, Error: err}, err
+		} else if run {
+			if err := da.runContainer(ctx, jobLogger, "pre", runs.PreEntrypoint); err != nil {
+				return &ActionResult{Success: false, Error: err}, err
+			}
+		} else {
+			jobLogger.LogStepOutput(fmt.Sprintf("Skipping pre of %s because pre-if evaluated to false", da.Ref))
+		}
+	}
+
+	// with.entrypoint replaces runs.entrypoint, as it does on GitHub
+	entrypoint := runs.Entrypoint
+	if value, given := lookupInput(ctx.Inputs, "entrypoint"); given && value != "" {
+		entrypoint = value
+	}
+	if err := da.runContainer(ctx, jobLogger, "main", entrypoint); err != nil {
+		return &ActionResult{Success: false, Error: err}, err
+	}
+	return &ActionResult{Success: true, Outputs: make(map[string]string)}, nil
+}
+
+// HasPost reports whether the action declares a post-entrypoint
+func (da *DockerAction) HasPost() bool {
+	return da.Metadata.Runs.PostEntrypoint != ""
+}
+
+// ExecutePost runs the post-entrypoint; the job checks post-if before calling it
+func (da *DockerAction) ExecutePost(ctx *ActionContext, jobLogger *logging.JobLogger) (*ActionResult, error) {
+	if err := da.runContainer(ctx, jobLogger, "post", da.Metadata.Runs.PostEntrypoint); err != nil {
+		return &ActionResult{Success: false, Error: err}, err
+	}
+	return &ActionResult{Success: true, Outputs: make(map[string]string)}, nil
+}
+
+// shouldRun evaluates pre-if, which defaults to always()
+func (da *DockerAction) shouldRun(ctx *ActionContext, condition string) (bool, error) {
+	if condition == "" || ctx.EvaluateCondition == nil {
+		return true, nil
+	}
+	return ctx.EvaluateCondition(condition)
+}
+
+// runContainer runs one phase of the action in a container of its image
+func (da *DockerAction) runContainer(ctx *ActionContext, jobLogger *logging.JobLogger, phase, entrypoint string) error {
+	if ctx.JobRunner == nil {
+		return fmt.Errorf("docker action %s can only run inside a job", da.Ref)
+	}
+
+	image, err := da.image(ctx, jobLogger)
+	if err != nil {
+		return err
+	}
+	args, err := da.args(ctx)
+	if err != nil {
+		return err
+	}
+	env, err := da.env(ctx)
+	if err != nil {
+		return err
+	}
+
+	command := strings.TrimSpace(strings.Join(append([]string{entrypoint}, args...), " "))
+	jobLogger.LogStepOutput(fmt.Sprintf("Running %s of %s in %s: %s", phase, da.Ref, image, command))
+
+	action := container.ActionContainer{Image: image, Entrypoint: entrypoint, Args: args, Env: env}
+	result, err := ctx.JobRunner.RunActionContainer(da.Ref, action, jobLogger)
+	if err != nil {
+		return err
+	}
+	if !result.Success {
+		return fmt.Errorf("%s of %s failed: container exited with code %d", phase, da.Ref, result.ExitCode)
+	}
+	return nil
+}
+
+// image returns the image to run, building the action's Dockerfile when no image
+// for its current contents exists yet
+func (da *DockerAction) image(ctx *ActionContext, jobLogger *logging.JobLogger) (string, error) {
+	image := da.Metadata.Runs.Image
+	if name, isImage := strings.CutPrefix(image, dockerImagePrefix); isImage {
+		return name, nil
+	}
+	if da.builtImage != "" {
+		return da.builtImage, nil
+	}
+
+	hash, err := hashDirectory(da.Dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash action %s: %w", da.Ref, err)
+	}
+	tag := "gogh-action:" + hash[:16]
+	if container.ImageExists(tag) {
+		jobLogger.LogStepOutput(fmt.Sprintf("Using image %s built from %s", tag, image))
+	} else {
+		jobLogger.LogStepOutput(fmt.Sprintf("Building image %s from %s", tag, image))
+		dockerfile := filepath.Join(da.Dir, filepath.FromSlash(image))
+		if err := ctx.JobRunner.BuildImage(da.Dir, dockerfile, tag, jobLogger); err != nil {
+			return "", fmt.Errorf("action %s: %w", da.Ref, err)
+		}
+	}
+
+	da.builtImage = tag
+	return tag, nil
+}
+
+// args returns the container's arguments: with.args split like a shell would, or
+// else runs.args with their expressions evaluated
+func (da *DockerAction) args(ctx *ActionContext) ([]string, error) {
+	if value, given := lookupInput(ctx.Inputs, "args"); given && value != "" {
+		return splitArgs(value)
+	}
+
+	args := make([]string, 0, len(da.Metadata.Runs.Args))
+	for _, arg := range da.Metadata.Runs.Args {
+		value, err := da.interpolate(ctx, arg)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, value)
+	}
+	return args, nil
+}
+
+// env returns the container's environment: the step's variables except PATH, which
+// belongs to the image, then the inputs as INPUT_* variables, then runs.env
+func (da *DockerAction) env(ctx *ActionContext) (map[string]string, error) {
+	env := make(map[string]string, len(ctx.Env)+len(ctx.Inputs)+len(da.Metadata.Runs.Env))
+	for key, value := range ctx.Env {
+		if key != "PATH" {
+			env[key] = value
+		}
+	}
+	for name, value := range ctx.Inputs {
+		env[InputEnvName(name)] = value
+	}
+	for key, value := range da.Metadata.Runs.Env {
+		expanded, err := da.interpolate(ctx, value)
+		if err != nil {
+			return nil, err
+		}
+		env[key] = expanded
+	}
+	env["GITHUB_ACTION_REPOSITORY"], env["GITHUB_ACTION_REF"] = actionRepository(da.Ref)
+	return env, nil
+}
+
+func (da *DockerAction) interpolate(ctx *ActionContext, value string) (string, error) {
+	if ctx.Interpolate == nil {
+		return value, nil
+	}
+	return ctx.Interpolate(value)
+}
+
+// hashDirectory hashes the names and contents of the files in an action, so its image
+// is rebuilt whenever the Dockerfile or anything it copies changes
+func hashDirectory(dir string) (string, error) {
+	hash := sha256.New()
+	err := filepath.WalkDir(dir, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if entry.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.Name() == cacheMarkerFile {
+			return nil
+		}
+
+		relative, err := filepath.Rel(dir, file)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(hash, "%s\x00%o\x00", filepath.ToSlash(relative), entry.Type())
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(hash, f)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// splitArgs splits with.args into words the way a shell would: on whitespace,
+// keeping quoted strings together and honouring backslash escapes
+func splitArgs(value string) ([]string, error) {
+	var args []string
+	var word strings.Builder
+	inWord := false
+	var quote byte
+
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else if c == '\\' && quote == '"' && i+1 < len(value) && strings.IndexByte(`"\$`+"`", value[i+1]) != -1 {
+				i++
+				word.WriteByte(value[i])
+			} else {
+				word.WriteByte(c)
+			}
+		case c == '\'' || c == '"':
+			quote = c
+			inWord = true
+		case c == '\\' && i+1 < len(value):
+			i++
+			word.WriteByte(value[i])
+			inWord = true
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			if inWord {
+				args = append(args, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteByte(c)
+			inWord = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote in args: %s", quote, value)
+	}
+	if inWord {
+		args = append(args, word.String())
+	}
+	return args, nil
+}
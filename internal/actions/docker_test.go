@@ -0,0 +1,53 @@
+package actions
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitArgs(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  []string
+	}{
+		{"empty", "", nil},
+		{"blank", "  \t\n", nil},
+		{"words", "build --verbose", []string{"build", "--verbose"}},
+		{"repeated whitespace", "  a \t b\n\nc  ", []string{"a", "b", "c"}},
+		{"double quotes", `echo "hello world"`, []string{"echo", "hello world"}},
+		{"single quotes", `echo 'hello world'`, []string{"echo", "hello world"}},
+		{"empty quotes are an argument", `a "" ''`, []string{"a", "", ""}},
+		{"quotes join a word", `--name="my app"`, []string{"--name=my app"}},
+		{"adjacent quotes", `'a'"b"c`, []string{"abc"}},
+		{"single quotes are literal", `'a\"b $HOME'`, []string{`a\"b $HOME`}},
+		{"other quote inside quotes", `"it's" 'say "hi"'`, []string{"it's", `say "hi"`}},
+		{"double quote escapes", `"a\"b\\c\$d\` + "`" + `e"`, []string{"a\"b\\c$d`e"}},
+		{"other backslashes kept in double quotes", `"a\nb\x"`, []string{`a\nb\x`}},
+		{"backslash escapes outside quotes", `a\ b \"c\'`, []string{"a b", `"c'`}},
+		{"trailing backslash is kept", `a\`, []string{`a\`}},
+		{"no expansion", `$INPUT_NAME ${{ x }}`, []string{"$INPUT_NAME", "${{", "x", "}}"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := splitArgs(tt.value)
+			if err != nil {
+				t.Fatalf("splitArgs(%q) returned error: %v", tt.value, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitArgs(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitArgsErrors(t *testing.T) {
+	for _, value := range []string{`"unterminated`, `it's`, `"escaped quote\"`, `a 'b" c`} {
+		t.Run(value, func(t *testing.T) {
+			if got, err := splitArgs(value); err == nil {
+				t.Errorf("splitArgs(%q) = %q, want an error", value, got)
+			}
+		})
+	}
+}
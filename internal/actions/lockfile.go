@@ -0,0 +1,62 @@
+package actions
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ActionLockFile is the project file pinning the refs of vendored actions to commits
+const ActionLockFile = ".gogh/actions-lock.yml"
+
+// ActionLock pins the tag or branch of every vendored action repository to a commit,
+// so pulling the same workflows again fetches exactly the same code
+type ActionLock struct {
+	Actions map[string]LockedAction `yaml:"actions"` // Keyed by owner/repo@ref
+}
+
+// LockedAction is the commit a ref was pinned to
+type LockedAction struct {
+	SHA string `yaml:"sha"`
+}
+
+// LoadActionLock reads the lockfile of a project; a missing lockfile is empty
+func LoadActionLock(projectDir string) (*ActionLock, error) {
+	lock := &ActionLock{}
+
+	path := filepath.Join(projectDir, ActionLockFile)
+	content, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if err := yaml.Unmarshal(content, lock); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", ActionLockFile, err)
+		}
+	case !os.IsNotExist(err):
+		return nil, fmt.Errorf("failed to read %s: %w", ActionLockFile, err)
+	}
+
+	if lock.Actions == nil {
+		lock.Actions = make(map[string]LockedAction)
+	}
+	return lock, nil
+}
+
+// Save writes the lockfile into the project
+func (al *ActionLock) Save(projectDir string) error {
+	content, err := yaml.Marshal(al)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", ActionLockFile, err)
+	}
+	content = append([]byte("# Generated by gogh actions pull. Commit this file to pin action versions.\n"), content...)
+
+	path := filepath.Join(projectDir, ActionLockFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to write %s: %w", ActionLockFile, err)
+	}
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", ActionLockFile, err)
+	}
+	return nil
+}
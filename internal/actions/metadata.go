@@ -0,0 +1,184 @@
+package actions
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Neoxs/gogh/internal/workflow"
+	"gopkg.in/yaml.v3"
+)
+
+// metadataFiles are the names an action's metadata file may have, in lookup order
+var metadataFiles = []string{"action.yml", "action.yaml"}
+
+// ActionMetadata is the parsed action.yml of an action
+type ActionMetadata struct {
+	Name        string                  `yaml:"name"`
+	Description string                  `yaml:"description,omitempty"`
+	Inputs      map[string]ActionInput  `yaml:"inputs,omitempty"`
+	Outputs     map[string]ActionOutput `yaml:"outputs,omitempty"`
+	Runs        ActionRuns              `yaml:"runs"`
+
+	anyInputs bool // docker:// steps have no action.yml, so every input is accepted
+}
+
+// ActionInput declares one input of an action
+type ActionInput struct {
+	Description        string  `yaml:"description,omitempty"`
+	Required           bool    `yaml:"required,omitempty"`
+	Default            *string `yaml:"default,omitempty"` // May contain expressions, e.g. ${{ github.token }}
+	DeprecationMessage string  `yaml:"deprecationMessage,omitempty"`
+}
+
+// ActionOutput declares one output of an action; composite actions map it from their steps
+type ActionOutput struct {
+	Description string `yaml:"description,omitempty"`
+	Value       string `yaml:"value,omitempty"` // e.g. ${{ steps.build.outputs.path }}
+}
+
+// ActionRuns describes how an action runs
+type ActionRuns struct {
+	Using string `yaml:"using"` // composite, node16, node20 or docker
+
+	// Composite actions
+	Steps []workflow.StepDefinition `yaml:"steps,omitempty"`
+
+	// JavaScript actions; scripts are relative to the action directory
+	Main   string `yaml:"main,omitempty"`
+	Pre    string `yaml:"pre,omitempty"`
+	PreIf  string `yaml:"pre-if,omitempty"` // Defaults to always()
+	Post   string `yaml:"post,omitempty"`
+	PostIf string `yaml:"post-if,omitempty"` // Defaults to always()
+
+	// Docker actions; args and env may use expressions such as ${{ inputs.path }}
+	Image          string            `yaml:"image,omitempty"` // A Dockerfile in the action, or docker://image
+	Entrypoint     string            `yaml:"entrypoint,omitempty"`
+	Args           []string          `yaml:"args,omitempty"`
+	Env            map[string]string `yaml:"env,omitempty"`
+	PreEntrypoint  string            `yaml:"pre-entrypoint,omitempty"`
+	PostEntrypoint string            `yaml:"post-entrypoint,omitempty"`
+}
+
+// LoadActionMetadata reads action.yml or action.yaml from an action's directory
+func LoadActionMetadata(dir string) (*ActionMetadata, error) {
+	for _, name := range metadataFiles {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read action metadata: %w", err)
+		}
+
+		var metadata ActionMetadata
+		if err := yaml.Unmarshal(data, &metadata); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", filepath.Join(dir, name), err)
+		}
+		if metadata.Runs.Using == "" {
+			return nil, fmt.Errorf("%s: runs.using is required", filepath.Join(dir, name))
+		}
+		return &metadata, nil
+	}
+	return nil, fmt.Errorf("no action.yml or action.yaml found in %s", dir)
+}
+
+// hasMetadata reports whether a directory contains an action
+func hasMetadata(dir string) bool {
+	for _, name := range metadataFiles {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateInputs checks a step's with: values against the declared inputs: required
+// inputs without a default must be given. An empty value only warns, see InputWarnings.
+func (m *ActionMetadata) ValidateInputs(inputs map[string]string) error {
+	var missing []string
+	for name, input := range m.Inputs {
+		if !input.Required || input.Default != nil {
+			continue
+		}
+		if _, given := lookupInput(inputs, name); !given {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("required input(s) not provided: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// ApplyDefaults returns the inputs completed with the default of every declared input
+// that was not given; expand evaluates the expressions a default may contain
+func (m *ActionMetadata) ApplyDefaults(inputs map[string]string, expand func(name, value string) string) map[string]string {
+	result := make(map[string]string, len(inputs)+len(m.Inputs))
+	for name, value := range inputs {
+		result[name] = value
+	}
+	for name, input := range m.Inputs {
+		if _, given := lookupInput(inputs, name); given || input.Default == nil {
+			continue
+		}
+		result[name] = expand(name, *input.Default)
+	}
+	return result
+}
+
+// InputWarnings reports given inputs that the action does not declare or has deprecated,
+// and required inputs given an empty value, e.g. a secret that was not passed locally
+func (m *ActionMetadata) InputWarnings(inputs map[string]string) []string {
+	if m.anyInputs {
+		return nil
+	}
+
+	var unexpected, warnings []string
+	for name, value := range inputs {
+		input, declared := lookupInput(m.Inputs, name)
+		switch {
+		case !declared:
+			unexpected = append(unexpected, name)
+		case input.DeprecationMessage != "":
+			warnings = append(warnings, fmt.Sprintf("Input '%s' has been deprecated with message: %s", name, input.DeprecationMessage))
+		case input.Required && value == "":
+			warnings = append(warnings, fmt.Sprintf("Input '%s' is required but was given an empty value", name))
+		}
+	}
+
+	if len(unexpected) > 0 {
+		sort.Strings(unexpected)
+		valid := make([]string, 0, len(m.Inputs))
+		for name := range m.Inputs {
+			valid = append(valid, name)
+		}
+		sort.Strings(valid)
+		warnings = append(warnings, fmt.Sprintf("Unexpected input(s) '%s', valid inputs are ['%s']",
+			strings.Join(unexpected, "', '"), strings.Join(valid, "', '")))
+	}
+	sort.Strings(warnings)
+	return warnings
+}
+
+// defaultValue returns the Default of an input declared in Go, for built-in actions
+func defaultValue(value string) *string {
+	return &value
+}
+
+// lookupInput finds an input by name; input names are case-insensitive
+func lookupInput[V any](values map[string]V, name string) (V, bool) {
+	if value, ok := values[name]; ok {
+		return value, true
+	}
+	for key, value := range values {
+		if strings.EqualFold(key, name) {
+			return value, true
+		}
+	}
+	var zero V
+	return zero, false
+}
@@ -0,0 +1,169 @@
+package actions
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/Neoxs/gogh/internal/environment"
+	"github.com/Neoxs/gogh/internal/logging"
+)
+
+// NodeAction runs an action whose action.yml has runs.using: node16 or node20. Its scripts run
+// in the job container with the node binary of that runtime; inputs are passed as INPUT_*
+// variables, and values saved to GITHUB_STATE reach later phases as STATE_* variables.
+type NodeAction struct {
+	Ref      string          // The uses: reference
+	Dir      string          // Action directory on the host
+	Path     string          // Action directory inside the job container
+	Metadata *ActionMetadata // Parsed action.yml
+}
+
+// newNodeAction checks that the scripts a JavaScript action names exist
+func newNodeAction(actionRef, dir, containerPath string, metadata *ActionMetadata) (*NodeAction, error) {
+	runs := metadata.Runs
+	if runs.Main == "" {
+		return nil, fmt.Errorf("action %s: runs.main is required for %s actions", actionRef, runs.Using)
+	}
+	for _, script := range []string{runs.Pre, runs.Main, runs.Post} {
+		if script == "" {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(script))); err != nil {
+			return nil, fmt.Errorf("action %s: script %s not found in %s", actionRef, script, dir)
+		}
+	}
+
+	return &NodeAction{Ref: actionRef, Dir: dir, Path: containerPath, Metadata: metadata}, nil
+}
+
+func (na *NodeAction) GetName() string {
+	return na.Ref
+}
+
+func (na *NodeAction) GetMetadata() *ActionMetadata {
+	return na.Metadata
+}
+
+// Execute runs the pre script, when its pre-if allows it, followed by the main script.
+// The pre script runs at the start of the step rather than at the start of the job.
+func (na *NodeAction) Execute(ctx *ActionContext, jobLogger *logging.JobLogger) (*ActionResult, error) {
+	runs := na.Metadata.Runs
+	if ctx.State == nil {
+		ctx.State = make(map[string]string)
+	}
+
+	if runs.Pre != "" {
+		if run, err := na.shouldRun(ctx, runs.PreIf); err != nil {
+			return &ActionResult{Success: false, Error: err}, err
+		} else if run {
+			if err := na.runScript(ctx, jobLogger, "pre", runs.Pre); err != nil {
+				return &ActionResult{Success: false, Error: err}, err
+			}
+		} else {
+			jobLogger.LogStepOutput(fmt.Sprintf("Skipping pre of %s because pre-if evaluated to false", na.Ref))
+		}
+	}
+
+	if err := na.runScript(ctx, jobLogger, "main", runs.Main); err != nil {
+		return &ActionResult{Success: false, Error: err}, err
+	}
+	return &ActionResult{Success: true, Outputs: make(map[string]string)}, nil
+}
+
+// HasPost reports whether the action declares a post script
+func (na *NodeAction) HasPost() bool {
+	return na.Metadata.Runs.Post != ""
+}
+
+// ExecutePost runs the post script; the job checks post-if before calling it
+func (na *NodeAction) ExecutePost(ctx *ActionContext, jobLogger *logging.JobLogger) (*ActionResult, error) {
+	if err := na.runScript(ctx, jobLogger, "post", na.Metadata.Runs.Post); err != nil {
+		return &ActionResult{Success: false, Error: err}, err
+	}
+	return &ActionResult{Success: true, Outputs: make(map[string]string)}, nil
+}
+
+// shouldRun evaluates pre-if, which defaults to always()
+func (na *NodeAction) shouldRun(ctx *ActionContext, condition string) (bool, error) {
+	if condition == "" || ctx.EvaluateCondition == nil {
+		return true, nil
+	}
+	return ctx.EvaluateCondition(condition)
+}
+
+// runScript runs one of the action's scripts with node and collects the state it saves
+func (na *NodeAction) runScript(ctx *ActionContext, jobLogger *logging.JobLogger, phase, script string) error {
+	if ctx.JobRunner == nil {
+		return fmt.Errorf("JavaScript action %s can only run inside a job", na.Ref)
+	}
+
+	node, err := ctx.JobRunner.NodeRuntime(na.Metadata.Runs.Using)
+	if err != nil {
+		return err
+	}
+
+	env := make(map[string]string, len(ctx.Env)+len(ctx.Inputs)+len(ctx.State)+2)
+	for key, value := range ctx.Env {
+		env[key] = value
+	}
+	for name, value := range ctx.Inputs {
+		env[InputEnvName(name)] = value
+	}
+	for name, value := range ctx.State {
+		env["STATE_"+name] = value
+	}
+	env["GITHUB_ACTION_PATH"] = na.Path
+	env["GITHUB_ACTION_REPOSITORY"], env["GITHUB_ACTION_REF"] = actionRepository(na.Ref)
+
+	scriptPath := path.Join(na.Path, script)
+	command := fmt.Sprintf("%s %s", shellQuote(node), shellQuote(scriptPath))
+	jobLogger.LogStepOutput(fmt.Sprintf("Running %s of %s: %s %s", phase, na.Ref, node, scriptPath))
+
+	result, err := ctx.JobRunner.RunStep(na.Ref, "", command, env, jobLogger)
+	if err != nil {
+		return err
+	}
+
+	// State is collected even when the script failed, so post can clean up
+	if stateFile := ctx.Env["GITHUB_STATE"]; stateFile != "" {
+		content, err := ctx.JobRunner.ReadFile(stateFile)
+		if err != nil {
+			return err
+		}
+		state, err := environment.ParseFileCommand(content)
+		if err != nil {
+			return fmt.Errorf("unable to process file command 'state': %w", err)
+		}
+		for name, value := range state {
+			ctx.State[name] = value
+		}
+	}
+
+	if !result.Success {
+		return fmt.Errorf("%s of %s failed: node exited with code %d", phase, na.Ref, result.ExitCode)
+	}
+	return nil
+}
+
+// actionRepository returns owner/repo and the ref of a marketplace action, empty for local actions
+func actionRepository(actionRef string) (string, string) {
+	ref, err := ParseActionRef(actionRef)
+	if err != nil {
+		return "", ""
+	}
+	return ref.Repository(), ref.Version
+}
+
+// InputEnvName returns the variable an input is passed in, like @actions/core's getInput expects:
+// INPUT_ followed by the name in upper case with spaces replaced by underscores
+func InputEnvName(name string) string {
+	return "INPUT_" + strings.ToUpper(strings.ReplaceAll(name, " ", "_"))
+}
+
+// shellQuote quotes a value for bash -c
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
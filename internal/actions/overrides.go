@@ -0,0 +1,254 @@
+package actions
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Neoxs/gogh/internal/logging"
+	"gopkg.in/yaml.v3"
+)
+
+// OverridesFile is the project file replacing actions that must not run locally
+const OverridesFile = ".gogh/overrides.yml"
+
+// ActionOverrides replaces actions for local runs, either every use of an action or a
+// single step. Action keys are uses: references; one without @ref matches every version.
+//
+//	actions:
+//	  aws-actions/configure-aws-credentials:
+//	    run: echo "Using local credentials"
+//	steps:
+//	  deploy:
+//	    outputs:
+//	      url: http://localhost:8080
+type ActionOverrides struct {
+	Actions map[string]*ActionOverride `yaml:"actions"` // By uses: reference
+	Steps   map[string]*ActionOverride `yaml:"steps"`   // By step id, which wins over the action
+}
+
+// ActionOverride says what runs instead of an action: a replacement action, an inline
+// run: stub, or nothing at all. Canned outputs are reported on top of what ran.
+type ActionOverride struct {
+	Uses    string            `yaml:"uses,omitempty"`    // Replacement action, resolved like any uses:
+	With    map[string]string `yaml:"with,omitempty"`    // Inputs for the replacement, on top of the step's with:
+	Run     string            `yaml:"run,omitempty"`     // Bash script run in the job container instead
+	Outputs map[string]string `yaml:"outputs,omitempty"` // Outputs the step reports
+}
+
+// LoadActionOverrides reads .gogh/overrides.yml from the project; without the file nothing is overridden
+func LoadActionOverrides(projectDir string) (*ActionOverrides, error) {
+	overrides := &ActionOverrides{}
+
+	content, err := os.ReadFile(filepath.Join(projectDir, OverridesFile))
+	switch {
+	case err == nil:
+		if err := yaml.Unmarshal(content, overrides); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", OverridesFile, err)
+		}
+	case !os.IsNotExist(err):
+		return nil, fmt.Errorf("failed to read %s: %w", OverridesFile, err)
+	}
+
+	if err := overrides.validate(); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", OverridesFile, err)
+	}
+	return overrides, nil
+}
+
+func (ao *ActionOverrides) validate() error {
+	for _, section := range []struct {
+		name      string
+		overrides map[string]*ActionOverride
+	}{{"actions", ao.Actions}, {"steps", ao.Steps}} {
+		keys := make([]string, 0, len(section.overrides))
+		for key := range section.overrides {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			if err := section.overrides[key].validate(); err != nil {
+				return fmt.Errorf("%s.%s: %w", section.name, key, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (o *ActionOverride) validate() error {
+	switch {
+	case o == nil:
+		return fmt.Errorf("expected uses, run or outputs")
+	case o.With != nil && o.Uses == "":
+		return fmt.Errorf("with requires uses")
+	case o.Uses != "" && o.Run != "":
+		return fmt.Errorf("uses and run cannot be used together")
+	case o.Uses == "" && o.Run == "" && o.Outputs == nil:
+		return fmt.Errorf("expected uses, run or outputs")
+	}
+	return nil
+}
+
+// Find returns the override for a step, matching its id first, then its uses: reference
+// exactly, then the reference without its version. It also returns the key that matched.
+func (ao *ActionOverrides) Find(stepID, actionRef string) (*ActionOverride, string) {
+	if ao == nil {
+		return nil, ""
+	}
+	if override, exists := ao.Steps[stepID]; exists && stepID != "" {
+		return override, "step " + stepID
+	}
+	if override, exists := ao.Actions[actionRef]; exists {
+		return override, actionRef
+	}
+	if name, _, versioned := strings.Cut(actionRef, "@"); versioned && !IsDockerImageRef(actionRef) {
+		if override, exists := ao.Actions[name]; exists {
+			return override, name
+		}
+	}
+	return nil, ""
+}
+
+// OverriddenAction runs what .gogh/overrides.yml configured in place of an action
+type OverriddenAction struct {
+	Ref      string // The uses: reference that was overridden
+	Match    string // The key of overrides.yml that matched
+	Override *ActionOverride
+	Target   ActionExecutor // The replacement action; nil for run: stubs and canned outputs
+
+	metadata *ActionMetadata
+}
+
+// newOverriddenAction resolves the replacement action of an override
+func (ar *ActionResolver) newOverriddenAction(actionRef, match string, override *ActionOverride) (*OverriddenAction, error) {
+	action := &OverriddenAction{Ref: actionRef, Match: match, Override: override}
+	if override.Uses == "" {
+		// A stub accepts whatever the real action was given
+		action.metadata = &ActionMetadata{Name: actionRef, Runs: ActionRuns{Using: "override"}, anyInputs: true}
+		return action, nil
+	}
+
+	target, err := ar.findAction(override.Uses)
+	if err != nil {
+		return nil, fmt.Errorf("override of %s in %s: %w", actionRef, OverridesFile, err)
+	}
+	action.Target = target
+	action.metadata = target.GetMetadata()
+	return action, nil
+}
+
+func (oa *OverriddenAction) GetName() string {
+	return oa.Ref
+}
+
+func (oa *OverriddenAction) GetMetadata() *ActionMetadata {
+	return oa.metadata
+}
+
+// Description says what runs instead of the action, for the display and the logs
+func (oa *OverriddenAction) Description() string {
+	var description string
+	switch {
+	case oa.Override.Uses != "":
+		description = "replaced by " + oa.Override.Uses
+	case oa.Override.Run != "":
+		description = "stubbed with run:"
+	default:
+		description = "skipped"
+	}
+	if len(oa.Override.Outputs) > 0 {
+		description += fmt.Sprintf(", %d canned output(s)", len(oa.Override.Outputs))
+	}
+	return fmt.Sprintf("overridden (%s) by %s", description, oa.Match)
+}
+
+// Execute runs the replacement action or the run: stub, then adds the canned outputs
+func (oa *OverriddenAction) Execute(ctx *ActionContext, jobLogger *logging.JobLogger) (*ActionResult, error) {
+	jobLogger.LogStepOutput(fmt.Sprintf("Action %s %s in %s", oa.Ref, oa.Description(), OverridesFile))
+
+	outputs := make(map[string]string)
+	switch {
+	case oa.Target != nil:
+		if ctx.Inputs == nil {
+			ctx.Inputs = make(map[string]string, len(oa.Override.With))
+		}
+		for name, value := range oa.Override.With {
+			ctx.Inputs[name] = value
+		}
+		result, err := oa.Target.Execute(ctx, jobLogger)
+		if err != nil || !result.Success {
+			return result, err
+		}
+		for name, value := range result.Outputs {
+			outputs[name] = value
+		}
+
+	case oa.Override.Run != "":
+		if err := oa.runStub(ctx, jobLogger); err != nil {
+			return &ActionResult{Success: false, Error: err}, err
+		}
+	}
+
+	for name, value := range oa.Override.Outputs {
+		expanded, err := oa.interpolate(ctx, value)
+		if err != nil {
+			return &ActionResult{Success: false, Error: err}, err
+		}
+		outputs[name] = expanded
+	}
+	return &ActionResult{Success: true, Outputs: outputs}, nil
+}
+
+// HasPost reports whether the replacement action has a post step
+func (oa *OverriddenAction) HasPost() bool {
+	post, ok := oa.Target.(PostActionExecutor)
+	return ok && post.HasPost()
+}
+
+// ExecutePost runs the post step of the replacement action
+func (oa *OverriddenAction) ExecutePost(ctx *ActionContext, jobLogger *logging.JobLogger) (*ActionResult, error) {
+	post, ok := oa.Target.(PostActionExecutor)
+	if !ok {
+		return &ActionResult{Success: true, Outputs: make(map[string]string)}, nil
+	}
+	return post.ExecutePost(ctx, jobLogger)
+}
+
+// runStub runs the override's script in the job container with the step's inputs as INPUT_* variables
+func (oa *OverriddenAction) runStub(ctx *ActionContext, jobLogger *logging.JobLogger) error {
+	if ctx.JobRunner == nil {
+		return fmt.Errorf("override of %s can only run inside a job", oa.Ref)
+	}
+
+	script, err := oa.interpolate(ctx, oa.Override.Run)
+	if err != nil {
+		return err
+	}
+
+	env := make(map[string]string, len(ctx.Env)+len(ctx.Inputs))
+	for key, value := range ctx.Env {
+		env[key] = value
+	}
+	for name, value := range ctx.Inputs {
+		env[InputEnvName(name)] = value
+	}
+
+	result, err := ctx.JobRunner.RunStep(oa.Ref, "", script, env, jobLogger)
+	if err != nil {
+		return err
+	}
+	if !result.Success {
+		return fmt.Errorf("override of %s failed: process completed with exit code %d", oa.Ref, result.ExitCode)
+	}
+	return nil
+}
+
+func (oa *OverriddenAction) interpolate(ctx *ActionContext, value string) (string, error) {
+	if ctx.Interpolate == nil {
+		return value, nil
+	}
+	return ctx.Interpolate(value)
+}
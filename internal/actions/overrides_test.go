@@ -0,0 +1,147 @@
+package actions
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestActionOverridesFind(t *testing.T) {
+	byStep := &ActionOverride{Run: "echo step"}
+	byRef := &ActionOverride{Run: "echo ref"}
+	byName := &ActionOverride{Run: "echo name"}
+	byImage := &ActionOverride{Run: "echo image"}
+	overrides := &ActionOverrides{
+		Actions: map[string]*ActionOverride{
+			"actions/checkout@v4":      byRef,
+			"actions/checkout":         byName,
+			"acme/deploy":              byName,
+			"docker://alpine":          byImage,
+			"docker://registry:5000/x": byImage,
+		},
+		Steps: map[string]*ActionOverride{
+			"checkout": byStep,
+		},
+	}
+
+	tests := []struct {
+		name      string
+		stepID    string
+		actionRef string
+		want      *ActionOverride
+		match     string
+	}{
+		{"step id wins over the action", "checkout", "actions/checkout@v4", byStep, "step checkout"},
+		{"step id matches any action", "checkout", "acme/other@v1", byStep, "step checkout"},
+		{"exact reference", "build", "actions/checkout@v4", byRef, "actions/checkout@v4"},
+		{"exact reference wins over the name", "", "actions/checkout@v4", byRef, "actions/checkout@v4"},
+		{"name matches every version", "", "actions/checkout@v3", byName, "actions/checkout"},
+		{"name without a version", "", "acme/deploy@main", byName, "acme/deploy"},
+		{"unversioned reference", "", "acme/deploy", byName, "acme/deploy"},
+		{"empty step id never matches a step", "", "acme/other@v1", nil, ""},
+		{"docker image", "", "docker://alpine", byImage, "docker://alpine"},
+		{"docker tag is not a version", "", "docker://alpine:3.20", nil, ""},
+		{"docker digest is not a version", "", "docker://alpine@sha256:abc", nil, ""},
+		{"docker registry port", "", "docker://registry:5000/x", byImage, "docker://registry:5000/x"},
+		{"no match", "deploy", "acme/other@v1", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, match := overrides.Find(tt.stepID, tt.actionRef)
+			if got != tt.want || match != tt.match {
+				t.Errorf("Find(%q, %q) = %v, %q; want %v, %q", tt.stepID, tt.actionRef, got, match, tt.want, tt.match)
+			}
+		})
+	}
+}
+
+func TestNilActionOverridesFind(t *testing.T) {
+	var overrides *ActionOverrides
+	if got, match := overrides.Find("checkout", "actions/checkout@v4"); got != nil || match != "" {
+		t.Errorf("Find() on nil overrides = %v, %q; want nothing", got, match)
+	}
+}
+
+func TestActionOverrideValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		override *ActionOverride
+		wantErr  string
+	}{
+		{"uses", &ActionOverride{Uses: "./local-action"}, ""},
+		{"uses with inputs", &ActionOverride{Uses: "./local-action", With: map[string]string{"a": "1"}}, ""},
+		{"run", &ActionOverride{Run: "echo hi"}, ""},
+		{"outputs only", &ActionOverride{Outputs: map[string]string{"url": "http://localhost"}}, ""},
+		{"empty outputs skip the action", &ActionOverride{Outputs: map[string]string{}}, ""},
+		{"run with outputs", &ActionOverride{Run: "echo hi", Outputs: map[string]string{"url": "x"}}, ""},
+		{"empty", nil, "expected uses, run or outputs"},
+		{"nothing set", &ActionOverride{}, "expected uses, run or outputs"},
+		{"with without uses", &ActionOverride{Run: "echo hi", With: map[string]string{"a": "1"}}, "with requires uses"},
+		{"uses and run", &ActionOverride{Uses: "./local-action", Run: "echo hi"}, "uses and run cannot be used together"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.override.validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validate() returned error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("validate() = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadActionOverrides(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"valid", "actions:\n  acme/deploy:\n    run: echo skipped\nsteps:\n  build:\n    outputs:\n      id: '1'\n", ""},
+		{"step without anything", "steps:\n  build:\n", "invalid .gogh/overrides.yml: steps.build: expected uses, run or outputs"},
+		{"first invalid action by name", "actions:\n  b/b:\n    with: {a: 1}\n  a/a:\n    uses: x/y@v1\n    run: echo\n", "actions.a/a: uses and run cannot be used together"},
+		{"invalid yaml", "actions: [", "failed to parse .gogh/overrides.yml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projectDir := t.TempDir()
+			path := filepath.Join(projectDir, OverridesFile)
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			overrides, err := LoadActionOverrides(projectDir)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("LoadActionOverrides() returned error: %v", err)
+				}
+				if got, _ := overrides.Find("build", "acme/deploy@v1"); got == nil || got.Outputs["id"] != "1" {
+					t.Errorf("LoadActionOverrides() did not load the step override: %+v", overrides.Steps)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadActionOverrides() = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	overrides, err := LoadActionOverrides(t.TempDir())
+	if err != nil {
+		t.Fatalf("LoadActionOverrides() without the file returned error: %v", err)
+	}
+	if got, _ := overrides.Find("build", "acme/deploy@v1"); got != nil {
+		t.Errorf("LoadActionOverrides() without the file overrides %v", got)
+	}
+}
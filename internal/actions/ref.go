@@ -0,0 +1,79 @@
+package actions
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// ActionRef is a parsed owner/repo[/path]@ref action reference
+type ActionRef struct {
+	Owner   string
+	Repo    string
+	Path    string // Subdirectory of the repository holding action.yml; empty for the root
+	Version string // Tag, branch or commit SHA after the @
+}
+
+// ParseActionRef parses a marketplace action reference such as actions/cache/save@v4.
+// References that would lead outside their repository in the action cache are rejected.
+func ParseActionRef(actionRef string) (ActionRef, error) {
+	name, version, found := strings.Cut(actionRef, "@")
+	if !found || version == "" {
+		return ActionRef{}, fmt.Errorf("invalid action reference '%s': expected owner/repo[/path]@ref", actionRef)
+	}
+
+	parts := strings.SplitN(name, "/", 3)
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return ActionRef{}, fmt.Errorf("invalid action reference '%s': expected owner/repo[/path]@ref", actionRef)
+	}
+
+	ref := ActionRef{Owner: parts[0], Repo: parts[1], Version: version}
+	if len(parts) == 3 {
+		ref.Path = strings.Trim(parts[2], "/")
+	}
+
+	for _, value := range []string{ref.Owner, ref.Repo, ref.Path, ref.Version} {
+		if hasParentSegment(value) {
+			return ActionRef{}, fmt.Errorf("invalid action reference '%s': '..' is not allowed", actionRef)
+		}
+	}
+	if ref.Owner == "." || ref.Repo == "." {
+		return ActionRef{}, fmt.Errorf("invalid action reference '%s': expected owner/repo[/path]@ref", actionRef)
+	}
+	return ref, nil
+}
+
+// hasParentSegment reports whether a / or \ separated value has a .. segment
+func hasParentSegment(value string) bool {
+	for _, segment := range strings.FieldsFunc(value, func(r rune) bool { return r == '/' || r == '\\' }) {
+		if segment == ".." {
+			return true
+		}
+	}
+	return false
+}
+
+// Repository returns owner/repo
+func (r ActionRef) Repository() string {
+	return r.Owner + "/" + r.Repo
+}
+
+// String returns the reference as written in uses:
+func (r ActionRef) String() string {
+	name := r.Repository()
+	if r.Path != "" {
+		name += "/" + r.Path
+	}
+	return name + "@" + r.Version
+}
+
+// CachedRepoDir returns where a repository is stored in the action cache:
+// <cache>/<owner>/<repo>/<ref>
+func (ar *ActionResolver) CachedRepoDir(ref ActionRef) string {
+	return filepath.Join(ar.cacheDir, ref.Owner, ref.Repo, ref.Version)
+}
+
+// CachedActionDir returns the directory holding an action's action.yml in the cache
+func (ar *ActionResolver) CachedActionDir(ref ActionRef) string {
+	return filepath.Join(ar.CachedRepoDir(ref), filepath.FromSlash(ref.Path))
+}
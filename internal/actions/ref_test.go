@@ -0,0 +1,63 @@
+package actions
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseActionRef(t *testing.T) {
+	tests := []struct {
+		uses string
+		want ActionRef
+	}{
+		{"actions/checkout@v4", ActionRef{Owner: "actions", Repo: "checkout", Version: "v4"}},
+		{"actions/cache/save@v4", ActionRef{Owner: "actions", Repo: "cache", Path: "save", Version: "v4"}},
+		{"acme/tools/lint/go/@main", ActionRef{Owner: "acme", Repo: "tools", Path: "lint/go", Version: "main"}},
+		{"acme/tools@release/v1", ActionRef{Owner: "acme", Repo: "tools", Version: "release/v1"}},
+		{"acme/tools@v1..v2", ActionRef{Owner: "acme", Repo: "tools", Version: "v1..v2"}},
+	}
+	for _, test := range tests {
+		got, err := ParseActionRef(test.uses)
+		if err != nil {
+			t.Errorf("ParseActionRef(%q): %v", test.uses, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("ParseActionRef(%q) = %+v, want %+v", test.uses, got, test.want)
+		}
+	}
+}
+
+func TestParseActionRefRejects(t *testing.T) {
+	for _, uses := range []string{
+		"actions/checkout",
+		"actions/checkout@",
+		"actions@v1",
+		"/checkout@v1",
+		"a/b/../../../x@v1",
+		"a/b/sub/..@v1",
+		"../b@v1",
+		"a/..@v1",
+		"./b@v1",
+		"a/b@../../x",
+		"a/b@v1/../../..",
+		`a/b@v1\..\..`,
+	} {
+		if ref, err := ParseActionRef(uses); err == nil {
+			t.Errorf("ParseActionRef(%q) = %+v, want an error", uses, ref)
+		}
+	}
+}
+
+func TestCachedActionDirStaysInRepository(t *testing.T) {
+	resolver := NewActionResolver(t.TempDir())
+	ref, err := ParseActionRef("acme/tools/lint@v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	repoDir := resolver.CachedRepoDir(ref)
+	if dir := resolver.CachedActionDir(ref); !strings.HasPrefix(dir, repoDir+string(filepath.Separator)) {
+		t.Errorf("CachedActionDir = %s, want a directory below %s", dir, repoDir)
+	}
+}
@@ -2,8 +2,11 @@ package actions
 
 import (
 	"fmt"
+	"path"
+	"path/filepath"
 	"strings"
 
+	"github.com/Neoxs/gogh/container"
 	"github.com/Neoxs/gogh/internal/logging"
 )
 
@@ -18,13 +21,22 @@ type ActionResult struct {
 type ActionExecutor interface {
 	Execute(ctx *ActionContext, jobLogger *logging.JobLogger) (*ActionResult, error)
 	GetName() string
-	ValidateInputs(inputs map[string]string) error
+	GetMetadata() *ActionMetadata // Declared inputs and outputs, used to validate and complete with: values
+}
+
+// PostActionExecutor is implemented by actions with a post step, which runs after
+// all main steps of the job, in reverse order, when runs.post-if allows it
+type PostActionExecutor interface {
+	ActionExecutor
+	HasPost() bool
+	ExecutePost(ctx *ActionContext, jobLogger *logging.JobLogger) (*ActionResult, error)
 }
 
 // ActionContext provides runtime context for action execution
 type ActionContext struct {
 	// Action configuration
 	ActionRef string // e.g., "actions/checkout@v4"
+	StepID    string // id: of the step, matched against the steps of .gogh/overrides.yml
 	Inputs    map[string]string
 
 	// Runtime environment
@@ -34,6 +46,14 @@ type ActionContext struct {
 	// GitHub context (simulated locally)
 	GitHub GitHubContext
 	Runner RunnerContext
+
+	// Set by the executor for actions that run in the job container
+	JobRunner         *container.JobRunner
+	Env               map[string]string                    // The step's environment, including file-command paths
+	RunSteps          CompositeStepRunner                  // Runs the steps of a composite action
+	EvaluateCondition func(condition string) (bool, error) // Evaluates pre-if
+	Interpolate       func(value string) (string, error)   // Evaluates ${{ }} in runs.args and runs.env, with the action's inputs
+	State             map[string]string                    // GITHUB_STATE saved by pre and main, passed to post as STATE_*
 }
 
 // GitHubContext simulates GitHub's context variables
@@ -63,13 +83,16 @@ type RunnerContext struct {
 // ActionResolver routes action execution to appropriate implementation
 type ActionResolver struct {
 	builtinActions map[string]ActionExecutor
-	cacheDir       string // For future marketplace actions
+	projectDir     string // Mounted as the workspace, so cached actions are visible in containers
+	cacheDir       string // Marketplace actions, laid out as <owner>/<repo>/<ref>/<path>
+	overrides      *ActionOverrides
 }
 
 // NewActionResolver creates a new action resolver with built-in actions
 func NewActionResolver(projectDir string) *ActionResolver {
 	resolver := &ActionResolver{
 		builtinActions: make(map[string]ActionExecutor),
+		projectDir:     projectDir,
 		cacheDir:       projectDir + "/.gogh/actions-cache",
 	}
 
@@ -79,21 +102,134 @@ func NewActionResolver(projectDir string) *ActionResolver {
 	return resolver
 }
 
-// ResolveAction determines how to execute the given action
+// SetOverrides makes ResolveAction replace the actions and steps listed in .gogh/overrides.yml
+func (ar *ActionResolver) SetOverrides(overrides *ActionOverrides) {
+	ar.overrides = overrides
+}
+
+// ResolveAction determines how to execute the given action. Overrides are consulted
+// first, so an overridden action does not need to exist locally.
 func (ar *ActionResolver) ResolveAction(actionRef string, inputs map[string]string, ctx *ActionContext) (ActionExecutor, error) {
+	var stepID string
+	if ctx != nil {
+		stepID = ctx.StepID
+	}
+
+	var executor ActionExecutor
+	var err error
+	if override, match := ar.overrides.Find(stepID, actionRef); override != nil {
+		executor, err = ar.newOverriddenAction(actionRef, match, override)
+		if len(override.With) > 0 {
+			merged := make(map[string]string, len(inputs)+len(override.With))
+			for name, value := range inputs {
+				merged[name] = value
+			}
+			for name, value := range override.With {
+				merged[name] = value
+			}
+			inputs = merged
+		}
+	} else {
+		executor, err = ar.findAction(actionRef)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := executor.GetMetadata().ValidateInputs(inputs); err != nil {
+		return nil, fmt.Errorf("invalid inputs for %s: %w", actionRef, err)
+	}
+	return executor, nil
+}
+
+// findAction locates an action: local actions in the workspace, docker:// images,
+// then built-in actions, then marketplace actions in the action cache
+func (ar *ActionResolver) findAction(actionRef string) (ActionExecutor, error) {
+	if IsLocalActionRef(actionRef) {
+		return ar.resolveLocalAction(actionRef)
+	}
+	if IsDockerImageRef(actionRef) {
+		return newDockerImageAction(actionRef), nil
+	}
+
 	// Check if it's a built-in action first
 	if executor, exists := ar.builtinActions[ar.normalizeActionRef(actionRef)]; exists {
-		if err := executor.ValidateInputs(inputs); err != nil {
-			return nil, fmt.Errorf("invalid inputs for %s: %w", actionRef, err)
-		}
 		return executor, nil
 	}
 
-	// Future: Handle marketplace actions here
-	// return ar.resolveMarketplaceAction(actionRef, inputs, ctx)
+	// Marketplace actions are read from the action cache
+	if executor, err := ar.resolveMarketplaceAction(actionRef); executor != nil || err != nil {
+		return executor, err
+	}
 
-	return nil, fmt.Errorf("action '%s' not supported (built-in actions available: %s)",
-		actionRef, ar.listSupportedActions())
+	return nil, fmt.Errorf("action '%s' not supported (built-in actions available: %s; other actions are read from %s)",
+		actionRef, ar.listSupportedActions(), ar.cacheDir)
+}
+
+// IsLocalActionRef reports whether uses: refers to an action in the workspace, e.g. ./.github/actions/build
+func IsLocalActionRef(actionRef string) bool {
+	return strings.HasPrefix(actionRef, "./")
+}
+
+// resolveLocalAction loads a ./path action relative to the workspace, which is the project directory
+func (ar *ActionResolver) resolveLocalAction(actionRef string) (ActionExecutor, error) {
+	if strings.Contains(actionRef, "@") {
+		return nil, fmt.Errorf("local action '%s' cannot specify a version; it always uses the workspace", actionRef)
+	}
+
+	dir := filepath.Join(ar.projectDir, filepath.FromSlash(actionRef))
+	if !hasMetadata(dir) {
+		return nil, fmt.Errorf("can't find 'action.yml' or 'action.yaml' for local action '%s' under %s", actionRef, dir)
+	}
+	return ar.loadAction(actionRef, dir)
+}
+
+// resolveMarketplaceAction loads an owner/repo[/path]@ref action from the action cache.
+// It returns nil without an error when the action is not cached.
+func (ar *ActionResolver) resolveMarketplaceAction(actionRef string) (ActionExecutor, error) {
+	ref, err := ParseActionRef(actionRef)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := ar.CachedActionDir(ref)
+	if !hasMetadata(dir) {
+		return nil, nil
+	}
+	return ar.loadAction(actionRef, dir)
+}
+
+// loadAction reads an action's metadata and picks the executor for its runs.using
+func (ar *ActionResolver) loadAction(actionRef, dir string) (ActionExecutor, error) {
+	metadata, err := LoadActionMetadata(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load action %s: %w", actionRef, err)
+	}
+
+	containerPath, err := ar.containerPath(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load action %s: %w", actionRef, err)
+	}
+
+	switch using := metadata.Runs.Using; {
+	case using == "composite":
+		return &CompositeAction{Ref: actionRef, Dir: dir, Path: containerPath, Metadata: metadata}, nil
+	case strings.HasPrefix(using, "node"):
+		return newNodeAction(actionRef, dir, containerPath, metadata)
+	case using == "docker":
+		return newDockerAction(actionRef, dir, metadata)
+	default:
+		return nil, fmt.Errorf("action %s has unknown runs.using '%s'; expected composite, node16, node20 or docker", actionRef, using)
+	}
+}
+
+// containerPath maps a directory in the project to where the job container sees it
+func (ar *ActionResolver) containerPath(dir string) (string, error) {
+	relative, err := filepath.Rel(ar.projectDir, dir)
+	if err != nil || relative == ".." || strings.HasPrefix(relative, "../") {
+		return "", fmt.Errorf("%s is outside the project directory", dir)
+	}
+	return path.Join("/workspace", filepath.ToSlash(relative)), nil
 }
 
 // registerBuiltinActions registers all internal action implementations
@@ -109,6 +245,12 @@ func (ar *ActionResolver) registerBuiltinActions() {
 	// Add more built-in actions as needed
 }
 
+// IsBuiltin reports whether an action is implemented by gogh rather than read from the cache
+func (ar *ActionResolver) IsBuiltin(actionRef string) bool {
+	_, exists := ar.builtinActions[ar.normalizeActionRef(actionRef)]
+	return exists
+}
+
 func (ar *ActionResolver) normalizeActionRef(actionRef string) string {
 	// Remove version tag for built-in matching: "actions/checkout@v4" -> "actions/checkout"
 	parts := strings.Split(actionRef, "@")
@@ -0,0 +1,302 @@
+package actions
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// DefaultRemoteURL is where action repositories are fetched from when online;
+// {repo} is replaced with owner/repo
+const DefaultRemoteURL = "https://github.com/{repo}.git"
+
+var commitSHAPattern = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// ActionSource fetches action repositories to populate the action cache
+type ActionSource interface {
+	// ResolveRef returns the commit a tag, branch or commit SHA of owner/repo points to
+	ResolveRef(repository, ref string) (string, error)
+	// Export writes the files of owner/repo at a commit into dest
+	Export(repository, sha, dest string) error
+	// String describes the source for messages
+	String() string
+}
+
+// remoteSource fetches repositories from a git server
+type remoteSource struct {
+	urlTemplate string
+}
+
+// NewRemoteSource fetches repositories from git URLs such as DefaultRemoteURL
+func NewRemoteSource(urlTemplate string) ActionSource {
+	return &remoteSource{urlTemplate: urlTemplate}
+}
+
+func (rs *remoteSource) url(repository string) string {
+	return strings.ReplaceAll(rs.urlTemplate, "{repo}", repository)
+}
+
+func (rs *remoteSource) ResolveRef(repository, ref string) (string, error) {
+	return resolveGitRef(rs.url(repository), ref)
+}
+
+func (rs *remoteSource) Export(repository, sha, dest string) error {
+	tempRepo, err := os.MkdirTemp("", "gogh-action-")
+	if err != nil {
+		return fmt.Errorf("failed to create a temporary repository: %w", err)
+	}
+	defer os.RemoveAll(tempRepo)
+
+	if _, err := runGit(tempRepo, "init", "-q", "--bare"); err != nil {
+		return err
+	}
+	if _, err := runGit(tempRepo, "fetch", "-q", "--depth", "1", rs.url(repository), sha); err != nil {
+		return fmt.Errorf("failed to fetch %s at %s: %w", repository, sha, err)
+	}
+	return exportCommit(tempRepo, sha, dest)
+}
+
+func (rs *remoteSource) String() string {
+	return rs.urlTemplate
+}
+
+// mirrorSource reads bare mirrors laid out as <dir>/<owner>/<repo>.git, e.g. made with
+// git clone --mirror, so nothing is fetched over the network
+type mirrorSource struct {
+	dir         string
+	description string
+}
+
+// NewMirrorSource reads repositories from a directory of bare mirrors
+func NewMirrorSource(dir string) (ActionSource, error) {
+	info, err := os.Stat(dir)
+	if err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("mirror directory %s does not exist", dir)
+	}
+	return &mirrorSource{dir: dir, description: dir}, nil
+}
+
+// NewBundleSource reads repositories from a tarball, optionally gzipped, holding the same
+// layout as a mirror directory. The returned function removes the unpacked bundle.
+func NewBundleSource(bundle string) (ActionSource, func(), error) {
+	file, err := os.Open(bundle)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open bundle: %w", err)
+	}
+	defer file.Close()
+
+	dir, err := os.MkdirTemp("", "gogh-bundle-")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to unpack bundle: %w", err)
+	}
+	cleanup := func() { os.RemoveAll(dir) }
+
+	reader := bufio.NewReader(file)
+	var archive io.Reader = reader
+	if magic, _ := reader.Peek(2); bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		gzipReader, err := gzip.NewReader(reader)
+		if err != nil {
+			cleanup()
+			return nil, nil, fmt.Errorf("failed to unpack bundle %s: %w", bundle, err)
+		}
+		defer gzipReader.Close()
+		archive = gzipReader
+	}
+
+	if err := extractTar(archive, dir); err != nil {
+		cleanup()
+		return nil, nil, fmt.Errorf("failed to unpack bundle %s: %w", bundle, err)
+	}
+	return &mirrorSource{dir: dir, description: bundle}, cleanup, nil
+}
+
+// repository returns the bare mirror of owner/repo, with or without the .git suffix
+func (ms *mirrorSource) repository(repository string) (string, error) {
+	base := filepath.Join(ms.dir, filepath.FromSlash(repository))
+	for _, candidate := range []string{base + ".git", base} {
+		if info, err := os.Stat(candidate); err == nil && info.IsDir() {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("%s has no mirror of %s (expected %s.git)", ms.description, repository, repository)
+}
+
+func (ms *mirrorSource) ResolveRef(repository, ref string) (string, error) {
+	mirror, err := ms.repository(repository)
+	if err != nil {
+		return "", err
+	}
+	return resolveGitRef(mirror, ref)
+}
+
+func (ms *mirrorSource) Export(repository, sha, dest string) error {
+	mirror, err := ms.repository(repository)
+	if err != nil {
+		return err
+	}
+	return exportCommit(mirror, sha, dest)
+}
+
+func (ms *mirrorSource) String() string {
+	return ms.description
+}
+
+// resolveGitRef looks a ref up in a repository; annotated tags resolve to their commit,
+// and tags win over branches of the same name, like on GitHub
+func resolveGitRef(location, ref string) (string, error) {
+	if commitSHAPattern.MatchString(ref) {
+		return ref, nil
+	}
+
+	output, err := runGit("", "ls-remote", location, "refs/tags/"+ref, "refs/tags/"+ref+"^{}", "refs/heads/"+ref)
+	if err != nil {
+		return "", fmt.Errorf("failed to look up %s in %s: %w", ref, location, err)
+	}
+
+	found := make(map[string]string)
+	for _, line := range strings.Split(string(output), "\n") {
+		if sha, name, ok := strings.Cut(strings.TrimSpace(line), "\t"); ok {
+			found[name] = sha
+		}
+	}
+	for _, name := range []string{"refs/tags/" + ref + "^{}", "refs/tags/" + ref, "refs/heads/" + ref} {
+		if sha, ok := found[name]; ok {
+			return sha, nil
+		}
+	}
+	return "", fmt.Errorf("ref '%s' not found in %s", ref, location)
+}
+
+// exportCommit writes the tree of a commit in a bare repository into dest
+func exportCommit(gitDir, sha, dest string) error {
+	output, err := runGit(gitDir, "archive", "--format=tar", sha)
+	if err != nil {
+		return fmt.Errorf("failed to export %s: %w", sha, err)
+	}
+	return extractTar(bytes.NewReader(output), dest)
+}
+
+// extractTar unpacks directories, files and symbolic links into dest. Archives come from
+// bundles and action repositories, so nothing may be written outside dest: links must
+// point inside it, and no entry is written through a link.
+func extractTar(archive io.Reader, dest string) error {
+	dest = filepath.Clean(dest)
+	reader := tar.NewReader(archive)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(dest, filepath.FromSlash(header.Name))
+		if !withinDir(dest, target) {
+			return fmt.Errorf("archive entry %s escapes the destination", header.Name)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := makeDirs(dest, target); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := makeDirs(dest, filepath.Dir(target)); err != nil {
+				return err
+			}
+			if err := refuseSymlink(target); err != nil {
+				return err
+			}
+			file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(header.Mode)&0777)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(file, reader)
+			file.Close()
+			if err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			if filepath.IsAbs(header.Linkname) || !withinDir(dest, filepath.Join(filepath.Dir(target), filepath.FromSlash(header.Linkname))) {
+				return fmt.Errorf("archive link %s -> %s escapes the destination", header.Name, header.Linkname)
+			}
+			if err := makeDirs(dest, filepath.Dir(target)); err != nil {
+				return err
+			}
+			if err := refuseSymlink(target); err != nil {
+				return err
+			}
+			if err := os.Symlink(header.Linkname, target); err != nil {
+				return err
+			}
+		}
+		// Other entries, such as the pax header git archive writes, carry no files
+	}
+}
+
+// withinDir reports whether path is dir or lies below it; both must be clean
+func withinDir(dir, path string) bool {
+	return path == dir || strings.HasPrefix(path, dir+string(filepath.Separator))
+}
+
+// makeDirs creates dir and its parents below dest, refusing to follow a symbolic
+// link on the way, so an earlier archive entry cannot redirect later ones
+func makeDirs(dest, dir string) error {
+	relative, err := filepath.Rel(dest, dir)
+	if err != nil {
+		return err
+	}
+	if relative == "." {
+		return nil
+	}
+
+	current := dest
+	for _, part := range strings.Split(relative, string(filepath.Separator)) {
+		current = filepath.Join(current, part)
+		info, err := os.Lstat(current)
+		switch {
+		case os.IsNotExist(err):
+			if err := os.Mkdir(current, 0755); err != nil {
+				return err
+			}
+		case err != nil:
+			return err
+		case info.Mode()&os.ModeSymlink != 0:
+			return fmt.Errorf("archive entry under %s would be written through a symbolic link", current)
+		case !info.IsDir():
+			return fmt.Errorf("archive entry under %s, which is not a directory", current)
+		}
+	}
+	return nil
+}
+
+// refuseSymlink fails when path is an existing symbolic link, which writing would follow
+func refuseSymlink(path string) error {
+	if info, err := os.Lstat(path); err == nil && info.Mode()&os.ModeSymlink != 0 {
+		return fmt.Errorf("archive entry %s would be written through a symbolic link", path)
+	}
+	return nil
+}
+
+// runGit runs a git command, in dir when it is set, and returns its output
+func runGit(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		if exitError, ok := err.(*exec.ExitError); ok && len(exitError.Stderr) > 0 {
+			return nil, fmt.Errorf("%s", strings.TrimSpace(string(exitError.Stderr)))
+		}
+		return nil, err
+	}
+	return output, nil
+}
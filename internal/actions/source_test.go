@@ -0,0 +1,119 @@
+package actions
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// tarEntry is one entry of a test archive; a Link makes it a symbolic link
+type tarEntry struct {
+	Name, Body, Link string
+	Dir              bool
+}
+
+func buildTar(t *testing.T, entries []tarEntry) *bytes.Reader {
+	t.Helper()
+	var buffer bytes.Buffer
+	writer := tar.NewWriter(&buffer)
+	for _, entry := range entries {
+		header := &tar.Header{Name: entry.Name, Mode: 0644, Typeflag: tar.TypeReg, Size: int64(len(entry.Body))}
+		switch {
+		case entry.Dir:
+			header = &tar.Header{Name: entry.Name, Mode: 0755, Typeflag: tar.TypeDir}
+		case entry.Link != "":
+			header = &tar.Header{Name: entry.Name, Mode: 0777, Typeflag: tar.TypeSymlink, Linkname: entry.Link}
+		}
+		if err := writer.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if header.Typeflag == tar.TypeReg {
+			if _, err := writer.Write([]byte(entry.Body)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return bytes.NewReader(buffer.Bytes())
+}
+
+func TestExtractTar(t *testing.T) {
+	dest := t.TempDir()
+	archive := buildTar(t, []tarEntry{
+		{Name: "action.yml", Body: "name: test\n"},
+		{Name: "dist/", Dir: true},
+		{Name: "dist/index.js", Body: "console.log(1)\n"},
+		{Name: "lib/util.js", Body: "module.exports = {}\n"},
+		{Name: "index.js", Link: "dist/index.js"},
+	})
+
+	if err := extractTar(archive, dest); err != nil {
+		t.Fatalf("extractTar: %v", err)
+	}
+	for file, want := range map[string]string{
+		"action.yml":    "name: test\n",
+		"dist/index.js": "console.log(1)\n",
+		"lib/util.js":   "module.exports = {}\n",
+		"index.js":      "console.log(1)\n",
+	} {
+		got, err := os.ReadFile(filepath.Join(dest, file))
+		if err != nil {
+			t.Errorf("%s: %v", file, err)
+		} else if string(got) != want {
+			t.Errorf("%s = %q, want %q", file, got, want)
+		}
+	}
+}
+
+func TestExtractTarRejectsHostileArchives(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []tarEntry
+	}{
+		{"parent path", []tarEntry{{Name: "../evil", Body: "x"}}},
+		{"nested parent path", []tarEntry{{Name: "a/../../evil", Body: "x"}}},
+		{"absolute link", []tarEntry{{Name: "link", Link: "/tmp"}, {Name: "link/evil", Body: "x"}}},
+		{"relative link out", []tarEntry{{Name: "link", Link: "../outside"}, {Name: "link/evil", Body: "x"}}},
+		{"deep relative link out", []tarEntry{{Name: "a/b/link", Link: "../../../outside"}}},
+		{"write through link in dest", []tarEntry{{Name: "real/", Dir: true}, {Name: "link", Link: "real"}, {Name: "link/evil", Body: "x"}}},
+		{"overwrite link", []tarEntry{{Name: "real", Body: "x"}, {Name: "link", Link: "real"}, {Name: "link", Body: "y"}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			root := t.TempDir()
+			dest := filepath.Join(root, "dest")
+			outside := filepath.Join(root, "outside")
+			for _, dir := range []string{dest, outside} {
+				if err := os.Mkdir(dir, 0755); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := extractTar(buildTar(t, test.entries), dest); err == nil {
+				t.Fatal("extractTar succeeded, want an error")
+			}
+
+			// Nothing may appear next to dest or in the directory a link pointed to
+			for _, dir := range []string{root, outside} {
+				entries, err := os.ReadDir(dir)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, entry := range entries {
+					if entry.Name() != "dest" && entry.Name() != "outside" {
+						t.Errorf("%s was written outside the destination", filepath.Join(dir, entry.Name()))
+					}
+				}
+			}
+			if content, err := os.ReadFile(filepath.Join(dest, "real")); err == nil && strings.TrimSpace(string(content)) != "x" {
+				t.Errorf("real was overwritten through a link: %q", content)
+			}
+		})
+	}
+}
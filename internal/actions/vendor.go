@@ -0,0 +1,225 @@
+package actions
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Neoxs/gogh/internal/workflow"
+)
+
+// cacheMarkerFile records which commit a cached repository holds
+const cacheMarkerFile = ".gogh-sha"
+
+// Pull statuses reported for each uses: reference
+const (
+	PullFetched = "fetched" // Exported from the source into the cache
+	PullCached  = "cached"  // Already in the cache at the locked commit
+	PullBuiltin = "builtin" // Implemented by gogh, nothing to fetch
+	PullSkipped = "skipped" // Not something the cache holds
+	PullFailed  = "failed"
+)
+
+// PullResult describes what happened to one uses: reference
+type PullResult struct {
+	Ref    string // The uses: reference, or the workflow file for parse errors
+	Status string
+	SHA    string // Commit the reference is pinned to
+	Err    error  // Why it failed, or why it was skipped
+}
+
+// ActionPuller fills the action cache with every action a set of workflows uses, following
+// reusable workflows and the steps of composite actions, so later runs need no network
+type ActionPuller struct {
+	resolver *ActionResolver
+	source   ActionSource
+	lock     *ActionLock
+	update   bool // Resolve refs again instead of using the commits in the lockfile
+
+	results      []PullResult
+	repositories map[string]PullResult // Repositories already handled, by owner/repo@ref
+	visited      map[string]bool       // uses: references and workflow files already scanned
+}
+
+// NewActionPuller creates a puller that stores actions where the resolver looks for them
+func NewActionPuller(resolver *ActionResolver, source ActionSource, lock *ActionLock, update bool) *ActionPuller {
+	return &ActionPuller{
+		resolver:     resolver,
+		source:       source,
+		lock:         lock,
+		update:       update,
+		repositories: make(map[string]PullResult),
+		visited:      make(map[string]bool),
+	}
+}
+
+// Pull scans the workflow files and caches every action they reference, pinning each
+// ref in the lockfile. It returns one result per uses: reference, in the order found.
+func (ap *ActionPuller) Pull(workflowFiles []string) []PullResult {
+	ap.results = nil
+	for _, file := range workflowFiles {
+		ap.scanWorkflow(file)
+	}
+	return ap.results
+}
+
+// scanWorkflow follows the uses: of every job and step of a workflow
+func (ap *ActionPuller) scanWorkflow(file string) {
+	if ap.visited[file] {
+		return
+	}
+	ap.visited[file] = true
+
+	workflowDef, err := workflow.NewParser().ParseFile(file)
+	if err != nil {
+		ap.results = append(ap.results, PullResult{Ref: file, Status: PullFailed, Err: err})
+		return
+	}
+
+	jobIDs := make([]string, 0, len(workflowDef.Jobs))
+	for jobID := range workflowDef.Jobs {
+		jobIDs = append(jobIDs, jobID)
+	}
+	sort.Strings(jobIDs)
+
+	for _, jobID := range jobIDs {
+		job := workflowDef.Jobs[jobID]
+		ap.scanUses(job.Uses)
+		ap.scanSteps(job.Steps)
+	}
+}
+
+func (ap *ActionPuller) scanSteps(steps []workflow.StepDefinition) {
+	for _, step := range steps {
+		ap.scanUses(step.Uses)
+	}
+}
+
+// scanUses caches what a uses: reference points to and scans it in turn
+func (ap *ActionPuller) scanUses(uses string) {
+	if uses == "" || ap.visited[uses] {
+		return
+	}
+	ap.visited[uses] = true
+
+	switch {
+	case strings.HasPrefix(uses, "docker://"):
+		ap.results = append(ap.results, PullResult{Ref: uses, Status: PullSkipped, Err: fmt.Errorf("docker images are not stored in the action cache")})
+		return
+
+	case IsLocalActionRef(uses):
+		// Local actions and reusable workflows live in the workspace; only what they use is fetched
+		target := filepath.Join(ap.resolver.projectDir, filepath.FromSlash(uses))
+		if isWorkflowFile(uses) {
+			ap.scanWorkflow(target)
+		} else {
+			ap.scanAction(uses, target)
+		}
+		return
+
+	case ap.resolver.IsBuiltin(uses):
+		ap.results = append(ap.results, PullResult{Ref: uses, Status: PullBuiltin})
+		return
+	}
+
+	ref, err := ParseActionRef(uses)
+	if err != nil {
+		ap.results = append(ap.results, PullResult{Ref: uses, Status: PullFailed, Err: err})
+		return
+	}
+
+	result := ap.pullRepository(ref)
+	result.Ref = uses
+	ap.results = append(ap.results, result)
+	if result.Status == PullFailed {
+		return
+	}
+
+	target := ap.resolver.CachedActionDir(ref)
+	if isWorkflowFile(ref.Path) {
+		ap.scanWorkflow(target)
+	} else {
+		ap.scanAction(uses, target)
+	}
+}
+
+// scanAction follows the steps of a composite action
+func (ap *ActionPuller) scanAction(uses, dir string) {
+	metadata, err := LoadActionMetadata(dir)
+	if err != nil {
+		ap.results = append(ap.results, PullResult{Ref: uses, Status: PullFailed, Err: err})
+		return
+	}
+	if metadata.Runs.Using == "composite" {
+		ap.scanSteps(metadata.Runs.Steps)
+	}
+}
+
+// pullRepository stores a repository at the commit its ref is pinned to
+func (ap *ActionPuller) pullRepository(ref ActionRef) PullResult {
+	key := ref.Repository() + "@" + ref.Version
+	if result, done := ap.repositories[key]; done {
+		if result.Status == PullFetched {
+			result.Status = PullCached
+		}
+		return result
+	}
+
+	result := ap.fetchRepository(key, ref)
+	ap.repositories[key] = result
+	return result
+}
+
+func (ap *ActionPuller) fetchRepository(key string, ref ActionRef) PullResult {
+	locked, isLocked := ap.lock.Actions[key]
+	sha := locked.SHA
+	if !isLocked || ap.update {
+		var err error
+		if sha, err = ap.source.ResolveRef(ref.Repository(), ref.Version); err != nil {
+			return PullResult{Status: PullFailed, Err: err}
+		}
+	}
+
+	dir := ap.resolver.CachedRepoDir(ref)
+	if cached, err := os.ReadFile(filepath.Join(dir, cacheMarkerFile)); err == nil && strings.TrimSpace(string(cached)) == sha {
+		ap.lock.Actions[key] = LockedAction{SHA: sha}
+		return PullResult{Status: PullCached, SHA: sha}
+	}
+
+	// Export next to the final directory, then swap it in, so a failed pull never leaves half a repository
+	if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+		return PullResult{Status: PullFailed, Err: fmt.Errorf("failed to create the action cache: %w", err)}
+	}
+	staging, err := os.MkdirTemp(filepath.Dir(dir), ".pull-")
+	if err != nil {
+		return PullResult{Status: PullFailed, Err: fmt.Errorf("failed to create the action cache: %w", err)}
+	}
+	defer os.RemoveAll(staging)
+	if err := os.Chmod(staging, 0755); err != nil {
+		return PullResult{Status: PullFailed, Err: fmt.Errorf("failed to create the action cache: %w", err)}
+	}
+
+	if err := ap.source.Export(ref.Repository(), sha, staging); err != nil {
+		return PullResult{Status: PullFailed, SHA: sha, Err: err}
+	}
+	if err := os.WriteFile(filepath.Join(staging, cacheMarkerFile), []byte(sha+"\n"), 0644); err != nil {
+		return PullResult{Status: PullFailed, SHA: sha, Err: err}
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return PullResult{Status: PullFailed, SHA: sha, Err: fmt.Errorf("failed to replace %s: %w", dir, err)}
+	}
+	if err := os.Rename(staging, dir); err != nil {
+		return PullResult{Status: PullFailed, SHA: sha, Err: fmt.Errorf("failed to store %s: %w", key, err)}
+	}
+
+	ap.lock.Actions[key] = LockedAction{SHA: sha}
+	return PullResult{Status: PullFetched, SHA: sha}
+}
+
+// isWorkflowFile reports whether a uses: path names a reusable workflow rather than an action
+func isWorkflowFile(usesPath string) bool {
+	extension := filepath.Ext(usesPath)
+	return extension == ".yml" || extension == ".yaml"
+}
@@ -5,27 +5,40 @@ import (
 	"os"
 	"os/exec"
 	"runtime"
+	"sort"
+	"strings"
+	"sync"
 	"time"
+
+	"github.com/Neoxs/gogh/internal/secrets"
 )
 
 // ExecutionStatus represents the current state of a workflow component
 type ExecutionStatus string
 
 const (
-	StatusPending ExecutionStatus = "pending"
-	StatusRunning ExecutionStatus = "running"
-	StatusSuccess ExecutionStatus = "success"
-	StatusFailure ExecutionStatus = "failure"
-	StatusSkipped ExecutionStatus = "skipped"
+	StatusPending   ExecutionStatus = "pending"
+	StatusRunning   ExecutionStatus = "running"
+	StatusSuccess   ExecutionStatus = "success"
+	StatusFailure   ExecutionStatus = "failure"
+	StatusSkipped   ExecutionStatus = "skipped"
+	StatusCancelled ExecutionStatus = "cancelled"
 )
 
-// WorkflowState holds the minimal state needed for display
+// CalledJobSeparator joins the name of a job calling a reusable workflow and the
+// names of the called jobs, as in "build / compile"
+const CalledJobSeparator = " / "
+
+// WorkflowState holds the minimal state needed for display.
+// Jobs may update it concurrently, so mutations go through its methods.
 type WorkflowState struct {
 	Name      string
 	Status    ExecutionStatus
 	StartTime time.Time
 	Jobs      map[string]*JobState
 	LogPath   string // Path to detailed logs
+
+	mu sync.RWMutex
 }
 
 // JobState holds the current state of a job execution
@@ -43,11 +56,17 @@ type StepState struct {
 	Status    ExecutionStatus
 	StartTime time.Time
 	EndTime   time.Time
+	Message   string       // Error or warning shown below the step
+	Note      string       // Shown after the name, e.g. when .gogh/overrides.yml replaced the step
+	SubSteps  []*StepState // Steps of a composite action, shown nested under it
 }
 
 // TerminalDisplay handles real-time workflow status display
 type TerminalDisplay struct {
 	lastRender time.Time
+	masker     *secrets.Masker  // Hides secret values in names and messages
+	workflows  []*WorkflowState // Set when several workflows share the display
+	mu         sync.Mutex       // Serializes renders from concurrent jobs
 }
 
 // NewTerminalDisplay creates a new terminal display manager
@@ -55,36 +74,104 @@ func NewTerminalDisplay() *TerminalDisplay {
 	return &TerminalDisplay{}
 }
 
+// SetMasker masks secret values in everything the display prints
+func (td *TerminalDisplay) SetMasker(masker *secrets.Masker) {
+	td.mu.Lock()
+	defer td.mu.Unlock()
+
+	td.masker = masker
+}
+
+// TrackWorkflow adds a workflow to a display shared by several workflows run together.
+// Every update then renders all of them, and the final result is shown by ShowRunComplete.
+func (td *TerminalDisplay) TrackWorkflow(state *WorkflowState) {
+	td.mu.Lock()
+	defer td.mu.Unlock()
+
+	td.workflows = append(td.workflows, state)
+}
+
 // UpdateWorkflowState renders the current workflow state to terminal
 func (td *TerminalDisplay) UpdateWorkflowState(state *WorkflowState) {
+	td.mu.Lock()
+	defer td.mu.Unlock()
+
 	td.clearScreen()
-	td.renderWorkflowTree(state)
+	td.render(state)
 	td.lastRender = time.Now()
 }
 
 // ShowWorkflowComplete displays final completion status
 func (td *TerminalDisplay) ShowWorkflowComplete(state *WorkflowState, totalDuration time.Duration) {
+	td.mu.Lock()
+	defer td.mu.Unlock()
+
 	td.clearScreen()
-	td.renderWorkflowTree(state)
+	td.render(state)
+	if len(td.workflows) > 0 {
+		return // The run summary is shown once every workflow has finished
+	}
 	fmt.Printf("\n🎉 Workflow completed successfully in %v\n", totalDuration)
 	fmt.Printf("📁 Detailed logs saved to: %s\n", state.LogPath)
 }
 
 // ShowWorkflowError displays error status
 func (td *TerminalDisplay) ShowWorkflowError(state *WorkflowState, err error) {
+	td.mu.Lock()
+	defer td.mu.Unlock()
+
 	td.clearScreen()
-	td.renderWorkflowTree(state)
-	fmt.Printf("\n❌ Workflow failed: %v\n", err)
+	td.render(state)
+	if len(td.workflows) > 0 {
+		return // The run summary is shown once every workflow has finished
+	}
+	fmt.Printf("\n❌ Workflow failed: %s\n", td.masker.Mask(err.Error()))
 	fmt.Printf("📁 Logs available at: %s\n", state.LogPath)
 }
 
+// ShowRunComplete displays the outcome of several workflows run together
+func (td *TerminalDisplay) ShowRunComplete(logRoot string, totalDuration time.Duration, failed []string) {
+	td.mu.Lock()
+	defer td.mu.Unlock()
+
+	td.clearScreen()
+	td.render(nil)
+	if len(failed) == 0 {
+		fmt.Printf("\n🎉 All %d workflows completed successfully in %v\n", len(td.workflows), totalDuration)
+		fmt.Printf("📁 Detailed logs saved to: %s\n", logRoot)
+		return
+	}
+	fmt.Printf("\n❌ %d of %d workflows failed: %s\n", len(failed), len(td.workflows), td.masker.Mask(strings.Join(failed, ", ")))
+	fmt.Printf("📁 Logs available at: %s\n", logRoot)
+}
+
+// render draws the given workflow, or every tracked workflow when the display is shared
+func (td *TerminalDisplay) render(state *WorkflowState) {
+	if len(td.workflows) == 0 {
+		td.renderWorkflowTree(state)
+	} else {
+		for i, workflow := range td.workflows {
+			if i > 0 {
+				fmt.Println()
+			}
+			td.renderWorkflowTree(workflow)
+		}
+	}
+
+	// Show current time for context
+	fmt.Printf("\n⏰ Last updated: %s", time.Now().Format("15:04:05"))
+}
+
 // renderWorkflowTree draws the hierarchical tree view
 func (td *TerminalDisplay) renderWorkflowTree(state *WorkflowState) {
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+
 	// Workflow header
 	duration := td.formatDuration(time.Since(state.StartTime))
 	statusIcon := td.getStatusIcon(state.Status)
 
-	fmt.Printf("%s Workflow: %s", statusIcon, state.Name)
+	fmt.Printf("%s Workflow: %s", statusIcon, td.masker.Mask(state.Name))
 	if state.Status == StatusRunning {
 		fmt.Printf(" (%s)", duration)
 	} else if state.Status == StatusSuccess || state.Status == StatusFailure {
@@ -100,9 +187,6 @@ func (td *TerminalDisplay) renderWorkflowTree(state *WorkflowState) {
 		isLast := i == len(jobIDs)-1
 		td.renderJob(job, isLast)
 	}
-
-	// Show current time for context
-	fmt.Printf("\n⏰ Last updated: %s", time.Now().Format("15:04:05"))
 }
 
 // renderJob draws a single job and its steps
@@ -118,7 +202,7 @@ func (td *TerminalDisplay) renderJob(job *JobState, isLastJob bool) {
 	statusIcon := td.getStatusIcon(job.Status)
 	jobDuration := td.getJobDuration(job)
 
-	fmt.Printf("%s %s %s", jobPrefix, statusIcon, job.ID)
+	fmt.Printf("%s %s %s", jobPrefix, statusIcon, td.masker.Mask(job.ID))
 	if jobDuration != "" {
 		fmt.Printf(" (%s)", jobDuration)
 	}
@@ -141,11 +225,27 @@ func (td *TerminalDisplay) renderStep(step *StepState, parentPrefix string, isLa
 	statusIcon := td.getStatusIcon(step.Status)
 	stepDuration := td.getStepDuration(step)
 
-	fmt.Printf("%s%s %s %s", parentPrefix, stepIcon, statusIcon, step.Name)
+	fmt.Printf("%s%s %s %s", parentPrefix, stepIcon, statusIcon, td.masker.Mask(step.Name))
 	if stepDuration != "" {
 		fmt.Printf(" (%s)", stepDuration)
 	}
+	if step.Note != "" {
+		fmt.Printf(" %s", td.masker.Mask(step.Note))
+	}
 	fmt.Println()
+
+	childPrefix := parentPrefix + "│   "
+	if isLastStep {
+		childPrefix = parentPrefix + "    "
+	}
+
+	if step.Message != "" {
+		fmt.Printf("%s%s\n", childPrefix, td.masker.Mask(step.Message))
+	}
+
+	for i, subStep := range step.SubSteps {
+		td.renderStep(subStep, childPrefix, i == len(step.SubSteps)-1)
+	}
 }
 
 // Helper methods
@@ -162,6 +262,8 @@ func (td *TerminalDisplay) getStatusIcon(status ExecutionStatus) string {
 		return "❌"
 	case StatusSkipped:
 		return "⏭️"
+	case StatusCancelled:
+		return "🚫"
 	default:
 		return "❓"
 	}
@@ -171,7 +273,7 @@ func (td *TerminalDisplay) getJobDuration(job *JobState) string {
 	switch job.Status {
 	case StatusRunning:
 		return td.formatDuration(time.Since(job.StartTime))
-	case StatusSuccess, StatusFailure:
+	case StatusSuccess, StatusFailure, StatusCancelled:
 		if !job.EndTime.IsZero() {
 			return td.formatDuration(job.EndTime.Sub(job.StartTime))
 		}
@@ -206,25 +308,50 @@ func (td *TerminalDisplay) formatDuration(d time.Duration) string {
 }
 
 func (td *TerminalDisplay) getSortedJobIDs(jobs map[string]*JobState) []string {
-	// Simple approach: sort by start time (jobs that started first appear first)
+	// Simple approach: sort by start time (jobs that started first appear first).
+	// Jobs of a called workflow stay under their calling job, so each job is keyed by
+	// the start times of its callers followed by its own.
 	type jobEntry struct {
-		id        string
-		startTime time.Time
+		id         string
+		startTimes []time.Time
 	}
 
 	var entries []jobEntry
 	for id, job := range jobs {
-		entries = append(entries, jobEntry{id: id, startTime: job.StartTime})
+		entry := jobEntry{id: id}
+		segments := strings.Split(id, CalledJobSeparator)
+		for i := 1; i < len(segments); i++ {
+			var callerStart time.Time
+			if caller, exists := jobs[strings.Join(segments[:i], CalledJobSeparator)]; exists {
+				callerStart = caller.StartTime
+			}
+			entry.startTimes = append(entry.startTimes, callerStart)
+		}
+		entry.startTimes = append(entry.startTimes, job.StartTime)
+		entries = append(entries, entry)
 	}
 
-	// Sort by start time
-	for i := 0; i < len(entries)-1; i++ {
-		for j := i + 1; j < len(entries); j++ {
-			if entries[i].startTime.After(entries[j].startTime) {
-				entries[i], entries[j] = entries[j], entries[i]
+	// Sort by start time, falling back to ID so pending jobs keep a stable order
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		for k := 0; k < len(a.startTimes) && k < len(b.startTimes); k++ {
+			if !a.startTimes[k].Equal(b.startTimes[k]) {
+				return a.startTimes[k].Before(b.startTimes[k])
+			}
+			if k < len(a.startTimes)-1 || k < len(b.startTimes)-1 {
+				// Same start so far; keep jobs of different callers apart
+				callerA := strings.Join(strings.Split(a.id, CalledJobSeparator)[:k+1], CalledJobSeparator)
+				callerB := strings.Join(strings.Split(b.id, CalledJobSeparator)[:k+1], CalledJobSeparator)
+				if callerA != callerB {
+					return callerA < callerB
+				}
 			}
 		}
-	}
+		if len(a.startTimes) != len(b.startTimes) {
+			return len(a.startTimes) < len(b.startTimes)
+		}
+		return a.id < b.id
+	})
 
 	var result []string
 	for _, entry := range entries {
@@ -280,39 +407,212 @@ func NewStepState(stepName string) *StepState {
 
 // State update methods
 
+// SetStatus updates the overall workflow status
+func (ws *WorkflowState) SetStatus(status ExecutionStatus) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	ws.Status = status
+}
+
+// AddJob registers a job so it appears in the display
+func (ws *WorkflowState) AddJob(job *JobState) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	ws.Jobs[job.ID] = job
+}
+
+// RemoveJob drops a job from the display, e.g. a placeholder replaced by its matrix instances
+func (ws *WorkflowState) RemoveJob(jobID string) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	delete(ws.Jobs, jobID)
+}
+
+// RemoveJobs drops every job whose ID starts with prefix, e.g. the jobs shown for a
+// reusable workflow before the calling job knows its inputs
+func (ws *WorkflowState) RemoveJobs(prefix string) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	for jobID := range ws.Jobs {
+		if strings.HasPrefix(jobID, prefix) {
+			delete(ws.Jobs, jobID)
+		}
+	}
+}
+
+// UpdatePendingJobs sets the status of every job that has not started and whose ID starts
+// with prefix, e.g. the jobs of a reusable workflow whose calling job was skipped
+func (ws *WorkflowState) UpdatePendingJobs(prefix string, status ExecutionStatus) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	for jobID, job := range ws.Jobs {
+		if !strings.HasPrefix(jobID, prefix) || job.Status != StatusPending {
+			continue
+		}
+		job.Status = status
+		for _, step := range job.Steps {
+			if step.Status == StatusPending {
+				step.Status = status
+			}
+		}
+	}
+}
+
+// SetStepMessageAt attaches an error or warning to the step at path, which lists the
+// indexes of the enclosing composite steps followed by the step's own index
+func (ws *WorkflowState) SetStepMessageAt(jobID string, path []int, message string) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	if step := ws.findStep(jobID, path); step != nil {
+		step.Message = message
+	}
+}
+
+// SetStepNoteAt attaches a note shown after the name of the step at path
+func (ws *WorkflowState) SetStepNoteAt(jobID string, path []int, note string) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	if step := ws.findStep(jobID, path); step != nil {
+		step.Note = note
+	}
+}
+
+// RenameStep updates the name of a job's step, e.g. once expressions in it are evaluated
+func (ws *WorkflowState) RenameStep(jobID string, index int, name string) {
+	ws.RenameStepAt(jobID, nil, index, name)
+}
+
+// RenameStepAt renames a step among the sub-steps of the step at parents,
+// or among the job's steps when parents is empty
+func (ws *WorkflowState) RenameStepAt(jobID string, parents []int, index int, name string) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	if steps := ws.stepList(jobID, parents); steps != nil && index >= 0 && index < len(*steps) {
+		(*steps)[index].Name = name
+	}
+}
+
+// SetSubSteps shows the steps of a composite action under the step at path
+func (ws *WorkflowState) SetSubSteps(jobID string, path []int, names []string) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	if step := ws.findStep(jobID, path); step != nil {
+		step.SubSteps = make([]*StepState, 0, len(names))
+		for _, name := range names {
+			step.SubSteps = append(step.SubSteps, NewStepState(name))
+		}
+	}
+}
+
+// stepList returns the job's steps, or the sub-steps of the step at parents
+func (ws *WorkflowState) stepList(jobID string, parents []int) *[]*StepState {
+	job, exists := ws.Jobs[jobID]
+	if !exists {
+		return nil
+	}
+
+	steps := &job.Steps
+	for _, index := range parents {
+		if index < 0 || index >= len(*steps) {
+			return nil
+		}
+		steps = &(*steps)[index].SubSteps
+	}
+	return steps
+}
+
+// findStep returns the step at path, or nil. Steps are found by position, since
+// two steps of a job may well have the same name.
+func (ws *WorkflowState) findStep(jobID string, path []int) *StepState {
+	if len(path) == 0 {
+		return nil
+	}
+	steps := ws.stepList(jobID, path[:len(path)-1])
+	index := path[len(path)-1]
+	if steps == nil || index < 0 || index >= len(*steps) {
+		return nil
+	}
+	return (*steps)[index]
+}
+
 // UpdateJobStatus updates a job's status and timing
 func (ws *WorkflowState) UpdateJobStatus(jobID string, status ExecutionStatus) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
 	if job, exists := ws.Jobs[jobID]; exists {
 		job.Status = status
 		if status == StatusRunning && job.StartTime.IsZero() {
 			job.StartTime = time.Now()
-		} else if (status == StatusSuccess || status == StatusFailure) && job.EndTime.IsZero() {
+		} else if (status == StatusSuccess || status == StatusFailure || status == StatusCancelled) && job.EndTime.IsZero() {
 			job.EndTime = time.Now()
 		}
-	}
-}
 
-// UpdateStepStatus updates a step's status and timing
-func (ws *WorkflowState) UpdateStepStatus(jobID, stepName string, status ExecutionStatus) {
-	if job, exists := ws.Jobs[jobID]; exists {
-		for _, step := range job.Steps {
-			if step.Name == stepName {
-				step.Status = status
-				if status == StatusRunning && step.StartTime.IsZero() {
-					step.StartTime = time.Now()
-				} else if (status == StatusSuccess || status == StatusFailure) && step.EndTime.IsZero() {
-					step.EndTime = time.Now()
+		// Steps that never started share the fate of a skipped or cancelled job
+		if status == StatusSkipped || status == StatusCancelled {
+			for _, step := range job.Steps {
+				if step.Status == StatusPending {
+					step.Status = status
 				}
-				return
 			}
 		}
 	}
 }
 
-// AddJobStep adds a new step to a job
-func (ws *WorkflowState) AddJobStep(jobID, stepName string) {
-	if job, exists := ws.Jobs[jobID]; exists {
-		step := NewStepState(stepName)
-		job.Steps = append(job.Steps, step)
+// UpdateStepStatusAt updates the status and timing of the step at path. Sub-steps that never
+// started share the fate of a composite step that finished, was skipped or was cancelled.
+func (ws *WorkflowState) UpdateStepStatusAt(jobID string, path []int, status ExecutionStatus) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	step := ws.findStep(jobID, path)
+	if step == nil {
+		return
+	}
+
+	step.Status = status
+	if status == StatusRunning && step.StartTime.IsZero() {
+		step.StartTime = time.Now()
+	} else if (status == StatusSuccess || status == StatusFailure) && step.EndTime.IsZero() {
+		step.EndTime = time.Now()
+	}
+
+	if status != StatusRunning {
+		settlePendingSteps(step.SubSteps, status)
+	}
+}
+
+// settlePendingSteps marks sub-steps that never started as skipped or cancelled
+func settlePendingSteps(steps []*StepState, status ExecutionStatus) {
+	if status != StatusCancelled {
+		status = StatusSkipped
+	}
+	for _, step := range steps {
+		if step.Status == StatusPending {
+			step.Status = status
+		}
+		settlePendingSteps(step.SubSteps, status)
+	}
+}
+
+// AddJobStep adds a new step to a job and returns its index, or -1 for an unknown job
+func (ws *WorkflowState) AddJobStep(jobID, stepName string) int {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	job, exists := ws.Jobs[jobID]
+	if !exists {
+		return -1
 	}
+	job.Steps = append(job.Steps, NewStepState(stepName))
+	return len(job.Steps) - 1
 }
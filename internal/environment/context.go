@@ -10,12 +10,22 @@ import (
 	"github.com/Neoxs/gogh/internal/workflow"
 )
 
+// ExpressionExpander evaluates the ${{ }} expressions in the value of an env: key,
+// using env as the env context
+type ExpressionExpander func(key, value string, env map[string]string) string
+
 // EnvironmentManager handles environment variable resolution and context
 type EnvironmentManager struct {
 	workflowEnv map[string]string
 	jobEnv      map[string]string
 	githubCtx   GitHubContext
 	runnerCtx   RunnerContext
+	expander    ExpressionExpander // Evaluates expressions in env: values
+
+	// State written by steps through file commands, scoped to one job
+	fileCommandEnv map[string]string // GITHUB_ENV
+	pathPrepends   []string          // GITHUB_PATH, most recent first
+	systemPath     string            // Container PATH the prepends are added to
 }
 
 // GitHubContext represents GitHub-specific context variables
@@ -31,6 +41,10 @@ type GitHubContext struct {
 	Job        string
 	Action     string
 	ActionPath string
+	HeadRef    string                 // Source branch of a pull request
+	BaseRef    string                 // Target branch of a pull request
+	Event      map[string]interface{} // Webhook payload, see SetEvent
+	EventPath  string                 // Payload file inside the container
 }
 
 // RunnerContext represents runner-specific context variables
@@ -54,11 +68,35 @@ func NewEnvironmentManager(workflowDef *workflow.WorkflowDefinition, projectDir
 	}
 }
 
+// SetExpander sets how expressions in env: values are evaluated
+func (em *EnvironmentManager) SetExpander(expander ExpressionExpander) {
+	em.expander = expander
+}
+
 // SetJobEnvironment sets job-level environment variables
 func (em *EnvironmentManager) SetJobEnvironment(jobEnv map[string]string) {
 	em.jobEnv = jobEnv
 }
 
+// ForJob returns a copy of the manager scoped to a single job, so jobs
+// running in parallel never share job-level state
+func (em *EnvironmentManager) ForJob(jobID string, jobEnv map[string]string) *EnvironmentManager {
+	jobManager := *em
+	jobManager.jobEnv = jobEnv
+	jobManager.githubCtx.Job = jobID
+	jobManager.fileCommandEnv = make(map[string]string)
+	jobManager.pathPrepends = nil
+	return &jobManager
+}
+
+// ForWorkflow returns a copy of the manager for a reusable workflow called by this run;
+// the github context is shared, while env: comes from the called workflow
+func (em *EnvironmentManager) ForWorkflow(workflowDef *workflow.WorkflowDefinition) *EnvironmentManager {
+	workflowManager := *em
+	workflowManager.workflowEnv = workflowDef.Env
+	return &workflowManager
+}
+
 // BuildStepEnvironment builds complete environment for a step with proper precedence
 func (em *EnvironmentManager) BuildStepEnvironment(stepEnv map[string]string) map[string]string {
 	env := make(map[string]string)
@@ -67,19 +105,35 @@ func (em *EnvironmentManager) BuildStepEnvironment(stepEnv map[string]string) ma
 	em.addGitHubContextVars(env)
 	em.addRunnerContextVars(env)
 
-	// 2. Workflow-level environment variables
+	// 2. Variables written to GITHUB_ENV by earlier steps, which env: overrides as on GitHub
+	for key, value := range em.fileCommandEnv {
+		env[key] = value
+	}
+
+	// 3. Workflow-level environment variables
 	for key, value := range em.workflowEnv {
-		env[key] = em.expandVariables(value, env)
+		env[key] = em.expandVariables(key, value, env)
 	}
 
-	// 3. Job-level environment variables
+	// 4. Job-level environment variables
 	for key, value := range em.jobEnv {
-		env[key] = em.expandVariables(value, env)
+		env[key] = em.expandVariables(key, value, env)
+	}
+
+	// 5. Directories written to GITHUB_PATH by earlier steps
+	if len(em.pathPrepends) > 0 {
+		entries := append([]string{}, em.pathPrepends...)
+		if basePath := env["PATH"]; basePath != "" {
+			entries = append(entries, basePath)
+		} else if em.systemPath != "" {
+			entries = append(entries, em.systemPath)
+		}
+		env["PATH"] = strings.Join(entries, ":")
 	}
 
-	// 4. Step-level environment variables (highest precedence)
+	// 6. Step-level environment variables (highest precedence)
 	for key, value := range stepEnv {
-		env[key] = em.expandVariables(value, env)
+		env[key] = em.expandVariables(key, value, env)
 	}
 
 	return env
@@ -98,6 +152,9 @@ func (em *EnvironmentManager) addGitHubContextVars(env map[string]string) {
 	env["GITHUB_JOB"] = em.githubCtx.Job
 	env["GITHUB_ACTION"] = em.githubCtx.Action
 	env["GITHUB_ACTION_PATH"] = em.githubCtx.ActionPath
+	env["GITHUB_EVENT_PATH"] = em.githubCtx.EventPath
+	env["GITHUB_HEAD_REF"] = em.githubCtx.HeadRef
+	env["GITHUB_BASE_REF"] = em.githubCtx.BaseRef
 
 	// Additional convenience variables
 	env["CI"] = "true"
@@ -113,33 +170,13 @@ func (em *EnvironmentManager) addRunnerContextVars(env map[string]string) {
 	env["RUNNER_TOOL_CACHE"] = em.runnerCtx.ToolCache
 }
 
-// expandVariables performs basic variable expansion
-func (em *EnvironmentManager) expandVariables(value string, currentEnv map[string]string) string {
-	result := value
-
-	// Handle ${{ github.* }} context variables
-	result = strings.ReplaceAll(result, "${{ github.repository }}", em.githubCtx.Repository)
-	result = strings.ReplaceAll(result, "${{ github.sha }}", em.githubCtx.SHA)
-	result = strings.ReplaceAll(result, "${{ github.ref }}", em.githubCtx.Ref)
-	result = strings.ReplaceAll(result, "${{ github.workspace }}", em.githubCtx.Workspace)
-	result = strings.ReplaceAll(result, "${{ github.event_name }}", em.githubCtx.EventName)
-	result = strings.ReplaceAll(result, "${{ github.actor }}", em.githubCtx.Actor)
-	result = strings.ReplaceAll(result, "${{ github.run_id }}", em.githubCtx.RunID)
-	result = strings.ReplaceAll(result, "${{ github.run_number }}", em.githubCtx.RunNumber)
-
-	// Handle ${{ runner.* }} context variables
-	result = strings.ReplaceAll(result, "${{ runner.os }}", em.runnerCtx.OS)
-	result = strings.ReplaceAll(result, "${{ runner.arch }}", em.runnerCtx.Arch)
-	result = strings.ReplaceAll(result, "${{ runner.temp }}", em.runnerCtx.Temp)
-	result = strings.ReplaceAll(result, "${{ runner.tool_cache }}", em.runnerCtx.ToolCache)
-
-	// Handle basic $VAR and ${VAR} expansion from current environment
-	for envKey, envValue := range currentEnv {
-		result = strings.ReplaceAll(result, fmt.Sprintf("$%s", envKey), envValue)
-		result = strings.ReplaceAll(result, fmt.Sprintf("${%s}", envKey), envValue)
+// expandVariables evaluates ${{ }} expressions through the expander, the same evaluator
+// as every other workflow field. $VAR references are left to the shell, as on GitHub.
+func (em *EnvironmentManager) expandVariables(key, value string, currentEnv map[string]string) string {
+	if em.expander == nil || !strings.Contains(value, "${{") {
+		return value
 	}
-
-	return result
+	return em.expander(key, value, currentEnv)
 }
 
 // GetGitHubContext returns the GitHub context for external use
@@ -225,6 +262,77 @@ func getGitActor(projectDir string) string {
 	return "local-user"
 }
 
+func getGitEmail(projectDir string) string {
+	cmd := fmt.Sprintf("cd %s && git config user.email 2>/dev/null", projectDir)
+	if output := executeCommand(cmd); output != "" {
+		return strings.TrimSpace(output)
+	}
+	return "local-user@users.noreply.github.com"
+}
+
+func getGitParentSHA(projectDir string) string {
+	cmd := fmt.Sprintf("cd %s && git rev-parse HEAD~1 2>/dev/null", projectDir)
+	if output := executeCommand(cmd); output != "" {
+		return strings.TrimSpace(output)
+	}
+	return "0000000000000000000000000000000000000000" // First commit, as in a new branch push
+}
+
+func getGitSubject(projectDir string) string {
+	cmd := fmt.Sprintf("cd %s && git log -1 --format=%%s 2>/dev/null", projectDir)
+	if output := executeCommand(cmd); output != "" {
+		return strings.TrimSpace(output)
+	}
+	return "Local changes"
+}
+
+// getGitHeadCommit describes HEAD the way push payloads list commits
+func getGitHeadCommit(projectDir, sha string) map[string]interface{} {
+	commit := map[string]interface{}{
+		"id":        sha,
+		"message":   getGitSubject(projectDir),
+		"timestamp": time.Now().Format(time.RFC3339),
+		"author": map[string]interface{}{
+			"name":  getGitActor(projectDir),
+			"email": getGitEmail(projectDir),
+		},
+	}
+
+	cmd := fmt.Sprintf("cd %s && git log -1 --format=%%cI 2>/dev/null", projectDir)
+	if output := executeCommand(cmd); output != "" {
+		commit["timestamp"] = strings.TrimSpace(output)
+	}
+	return commit
+}
+
+// getGitDefaultBranch returns the branch origin/HEAD points to, falling back to main
+func getGitDefaultBranch(projectDir string) string {
+	cmd := fmt.Sprintf("cd %s && git symbolic-ref refs/remotes/origin/HEAD 2>/dev/null", projectDir)
+	if output := executeCommand(cmd); output != "" {
+		return strings.TrimPrefix(strings.TrimSpace(output), "refs/remotes/origin/")
+	}
+	return "main"
+}
+
+// getGitBranchSHA resolves a branch locally or on origin, falling back to the given SHA
+func getGitBranchSHA(projectDir, branch, fallback string) string {
+	for _, candidate := range []string{branch, "origin/" + branch} {
+		cmd := fmt.Sprintf("cd %s && git rev-parse --verify --quiet %s 2>/dev/null", projectDir, candidate)
+		if output := executeCommand(cmd); output != "" {
+			return strings.TrimSpace(output)
+		}
+	}
+	return fallback
+}
+
+func getGitLatestTag(projectDir string) string {
+	cmd := fmt.Sprintf("cd %s && git describe --tags --abbrev=0 2>/dev/null", projectDir)
+	if output := executeCommand(cmd); output != "" {
+		return strings.TrimSpace(output)
+	}
+	return "v0.0.0"
+}
+
 func executeCommand(command string) string {
 	// Execute shell command and return output
 	parts := strings.Fields(command)
@@ -0,0 +1,216 @@
+package environment
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// EventPath is where the event payload is written inside job containers
+const EventPath = "/github/workflow/event.json"
+
+// DefaultEventName is the event simulated when --event is not given
+const DefaultEventName = "push"
+
+// Event is the webhook event that triggered the run, exposed as github.event
+type Event struct {
+	Name    string
+	Payload map[string]interface{}
+	Source  string // The payload file, or empty when the payload was synthesized
+}
+
+// LoadEvent reads the event payload from payloadPath, or synthesizes a default
+// payload for the event from the project's git state when no path is given
+func LoadEvent(name, payloadPath, projectDir string) (*Event, error) {
+	if name == "" {
+		name = DefaultEventName
+	}
+
+	var content []byte
+	var err error
+	if payloadPath == "" {
+		// Synthesized payloads go through JSON too, so they look exactly like loaded ones
+		content, err = json.Marshal(defaultEventPayload(name, projectDir))
+	} else {
+		content, err = os.ReadFile(payloadPath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read event payload: %w", err)
+	}
+
+	var payload map[string]interface{}
+	if err := json.Unmarshal(content, &payload); err != nil {
+		return nil, fmt.Errorf("failed to parse event payload %s: %w", payloadPath, err)
+	}
+	if payload == nil {
+		payload = make(map[string]interface{})
+	}
+
+	return &Event{Name: name, Payload: payload, Source: payloadPath}, nil
+}
+
+// Copy returns the event with its own top-level payload map, so workflows sharing one
+// loaded event can each store their inputs in it
+func (e *Event) Copy() *Event {
+	payload := make(map[string]interface{}, len(e.Payload))
+	for key, value := range e.Payload {
+		payload[key] = value
+	}
+	return &Event{Name: e.Name, Payload: payload, Source: e.Source}
+}
+
+// JSON returns the payload as written to GITHUB_EVENT_PATH
+func (e *Event) JSON() ([]byte, error) {
+	return json.MarshalIndent(e.Payload, "", "  ")
+}
+
+// Value follows a path of keys through the payload, e.g. Value("pull_request", "base", "ref");
+// nil if any key is missing
+func (e *Event) Value(keys ...string) interface{} {
+	return payloadValue(e.Payload, keys...)
+}
+
+// SetEvent makes the event visible as github.event and derives the refs and SHA
+// GitHub would report for it, e.g. refs/pull/<number>/merge for a pull request
+func (em *EnvironmentManager) SetEvent(event *Event) {
+	em.githubCtx.EventName = event.Name
+	em.githubCtx.Event = event.Payload
+	em.githubCtx.EventPath = EventPath
+
+	switch event.Name {
+	case "pull_request", "pull_request_target":
+		if number, ok := payloadValue(event.Payload, "number").(float64); ok && event.Name == "pull_request" {
+			em.githubCtx.Ref = fmt.Sprintf("refs/pull/%d/merge", int(number))
+		}
+		if head, ok := payloadValue(event.Payload, "pull_request", "head", "ref").(string); ok {
+			em.githubCtx.HeadRef = head
+		}
+		if base, ok := payloadValue(event.Payload, "pull_request", "base", "ref").(string); ok {
+			em.githubCtx.BaseRef = base
+			if event.Name == "pull_request_target" {
+				em.githubCtx.Ref = "refs/heads/" + base
+			}
+		}
+		if sha, ok := payloadValue(event.Payload, "pull_request", "head", "sha").(string); ok && sha != "" {
+			em.githubCtx.SHA = sha
+		}
+
+	case "release":
+		if tag, ok := payloadValue(event.Payload, "release", "tag_name").(string); ok && tag != "" {
+			em.githubCtx.Ref = "refs/tags/" + tag
+		}
+
+	default:
+		if ref, ok := payloadValue(event.Payload, "ref").(string); ok && ref != "" {
+			if !strings.HasPrefix(ref, "refs/") {
+				ref = "refs/heads/" + ref // workflow_dispatch payloads may carry a bare branch name
+			}
+			em.githubCtx.Ref = ref
+		}
+		if sha, ok := payloadValue(event.Payload, "after").(string); ok && sha != "" {
+			em.githubCtx.SHA = sha
+		}
+	}
+}
+
+// payloadValue follows a path of keys through a JSON payload; nil if any key is missing
+func payloadValue(payload map[string]interface{}, keys ...string) interface{} {
+	var current interface{} = payload
+	for _, key := range keys {
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = object[key]
+	}
+	return current
+}
+
+// defaultEventPayload synthesizes a payload resembling the webhook GitHub would send,
+// filled in from the local repository
+func defaultEventPayload(name, projectDir string) map[string]interface{} {
+	repository := getGitRepository(projectDir)
+	sha := getGitSHA(projectDir)
+	ref := getGitRef(projectDir)
+	branch := strings.TrimPrefix(ref, "refs/heads/")
+	defaultBranch := getGitDefaultBranch(projectDir)
+	actor := getGitActor(projectDir)
+
+	owner, repoName, _ := strings.Cut(repository, "/")
+	repositoryPayload := map[string]interface{}{
+		"name":           repoName,
+		"full_name":      repository,
+		"owner":          map[string]interface{}{"login": owner},
+		"default_branch": defaultBranch,
+		"html_url":       "https://github.com/" + repository,
+	}
+	sender := map[string]interface{}{"login": actor}
+
+	payload := map[string]interface{}{
+		"repository": repositoryPayload,
+		"sender":     sender,
+	}
+
+	switch name {
+	case "push":
+		headCommit := getGitHeadCommit(projectDir, sha)
+		payload["ref"] = ref
+		payload["before"] = getGitParentSHA(projectDir)
+		payload["after"] = sha
+		payload["created"] = false
+		payload["deleted"] = false
+		payload["forced"] = false
+		payload["base_ref"] = nil
+		payload["compare"] = fmt.Sprintf("https://github.com/%s/compare/%s", repository, branch)
+		payload["head_commit"] = headCommit
+		payload["commits"] = []interface{}{headCommit}
+		payload["pusher"] = map[string]interface{}{"name": actor, "email": getGitEmail(projectDir)}
+
+	case "pull_request", "pull_request_target":
+		number := 1
+		payload["action"] = "opened"
+		payload["number"] = number
+		payload["pull_request"] = map[string]interface{}{
+			"number":   number,
+			"title":    getGitSubject(projectDir),
+			"body":     "",
+			"state":    "open",
+			"draft":    false,
+			"merged":   false,
+			"labels":   []interface{}{},
+			"user":     sender,
+			"html_url": fmt.Sprintf("https://github.com/%s/pull/%d", repository, number),
+			"head": map[string]interface{}{
+				"ref":  branch,
+				"sha":  sha,
+				"repo": repositoryPayload,
+			},
+			"base": map[string]interface{}{
+				"ref":  defaultBranch,
+				"sha":  getGitBranchSHA(projectDir, defaultBranch, sha),
+				"repo": repositoryPayload,
+			},
+		}
+
+	case "workflow_dispatch":
+		payload["ref"] = ref
+		payload["inputs"] = map[string]interface{}{}
+
+	case "release":
+		tag := getGitLatestTag(projectDir)
+		payload["action"] = "published"
+		payload["release"] = map[string]interface{}{
+			"tag_name":         tag,
+			"name":             tag,
+			"body":             "",
+			"draft":            false,
+			"prerelease":       false,
+			"target_commitish": branch,
+			"author":           sender,
+			"html_url":         fmt.Sprintf("https://github.com/%s/releases/tag/%s", repository, tag),
+		}
+	}
+
+	return payload
+}
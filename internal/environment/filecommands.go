@@ -0,0 +1,109 @@
+package environment
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ParseFileCommand parses the contents of a GITHUB_ENV or GITHUB_OUTPUT file.
+// Each entry is either NAME=VALUE on one line, or NAME<<DELIMITER followed by
+// value lines and a line containing only DELIMITER. Later entries win.
+func ParseFileCommand(content string) (map[string]string, error) {
+	values := make(map[string]string)
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		equals := strings.Index(line, "=")
+		heredoc := strings.Index(line, "<<")
+
+		// NAME=VALUE, unless a << appears before the first =
+		if equals != -1 && (heredoc == -1 || equals < heredoc) {
+			name := line[:equals]
+			if name == "" {
+				return nil, fmt.Errorf("invalid format '%s': name must not be empty", line)
+			}
+			values[name] = line[equals+1:]
+			continue
+		}
+
+		if heredoc == -1 {
+			return nil, fmt.Errorf("invalid format '%s'", line)
+		}
+
+		name, delimiter := line[:heredoc], line[heredoc+2:]
+		if name == "" || delimiter == "" {
+			return nil, fmt.Errorf("invalid format '%s': name and delimiter must not be empty", line)
+		}
+
+		var valueLines []string
+		terminated := false
+		for i++; i < len(lines); i++ {
+			if lines[i] == delimiter {
+				terminated = true
+				break
+			}
+			valueLines = append(valueLines, lines[i])
+		}
+		if !terminated {
+			return nil, fmt.Errorf("matching delimiter not found '%s'", delimiter)
+		}
+
+		values[name] = strings.Join(valueLines, "\n")
+	}
+
+	return values, nil
+}
+
+// ParsePathFile returns the directories listed in a GITHUB_PATH file, one per line
+func ParsePathFile(content string) []string {
+	var paths []string
+	for _, line := range strings.Split(content, "\n") {
+		if entry := strings.TrimSpace(line); entry != "" {
+			paths = append(paths, entry)
+		}
+	}
+	return paths
+}
+
+// AddEnvironment records variables written to GITHUB_ENV; they apply to every later step of the job.
+// GITHUB_* and RUNNER_* variables belong to the runner and are not overwritten; their names are returned.
+func (em *EnvironmentManager) AddEnvironment(values map[string]string) []string {
+	var ignored []string
+	for key, value := range values {
+		if isProtectedVariable(key) {
+			ignored = append(ignored, key)
+			continue
+		}
+		em.fileCommandEnv[key] = value
+	}
+	sort.Strings(ignored)
+	return ignored
+}
+
+// isProtectedVariable reports whether a variable is set by the runner and cannot be changed through GITHUB_ENV
+func isProtectedVariable(name string) bool {
+	upper := strings.ToUpper(name)
+	return strings.HasPrefix(upper, "GITHUB_") || strings.HasPrefix(upper, "RUNNER_")
+}
+
+// PrependPath records a directory written to GITHUB_PATH; the most recent entry comes first
+func (em *EnvironmentManager) PrependPath(dir string) {
+	for i, existing := range em.pathPrepends {
+		if existing == dir {
+			em.pathPrepends = append(em.pathPrepends[:i], em.pathPrepends[i+1:]...)
+			break
+		}
+	}
+	em.pathPrepends = append([]string{dir}, em.pathPrepends...)
+}
+
+// SetSystemPath sets the container PATH that GITHUB_PATH entries are prepended to
+func (em *EnvironmentManager) SetSystemPath(path string) {
+	em.systemPath = path
+}
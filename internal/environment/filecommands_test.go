@@ -0,0 +1,182 @@
+package environment
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Neoxs/gogh/internal/workflow"
+)
+
+func TestParseFileCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    map[string]string
+	}{
+		{"empty", "", map[string]string{}},
+		{"single line", "NAME=value\n", map[string]string{"NAME": "value"}},
+		{"value keeps = and spaces", "OPTS= -a=1 -b \n", map[string]string{"OPTS": " -a=1 -b "}},
+		{"empty value", "EMPTY=\n", map[string]string{"EMPTY": ""}},
+		{"blank lines skipped", "\nA=1\n\n  \nB=2\n", map[string]string{"A": "1", "B": "2"}},
+		{"later entries win", "A=1\nA=2\n", map[string]string{"A": "2"}},
+		{
+			"heredoc",
+			"NOTES<<EOF\nfirst line\nsecond line\nEOF\n",
+			map[string]string{"NOTES": "first line\nsecond line"},
+		},
+		{"empty heredoc", "NOTES<<EOF\nEOF\n", map[string]string{"NOTES": ""}},
+		{
+			"heredoc keeps delimiter-like lines",
+			"NOTES<<EOF\n EOF\nEOF2\nEOF\n",
+			map[string]string{"NOTES": " EOF\nEOF2"},
+		},
+		{"crlf line endings", "A=1\r\nNOTES<<EOF\r\nline\r\nEOF\r\n", map[string]string{"A": "1", "NOTES": "line"}},
+		{"= before << is a plain value", "A=x<<y\n", map[string]string{"A": "x<<y"}},
+		{"<< before = is a heredoc", "A<<D=1\nvalue\nD=1\n", map[string]string{"A": "value"}},
+		{
+			"heredoc followed by entries",
+			"A<<EOF\nmulti\nEOF\nB=2\nA=3\n",
+			map[string]string{"A": "3", "B": "2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFileCommand(tt.content)
+			if err != nil {
+				t.Fatalf("ParseFileCommand(%q) returned error: %v", tt.content, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseFileCommand(%q) = %q, want %q", tt.content, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFileCommandErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"no separator", "NAME\n"},
+		{"empty name", "=value\n"},
+		{"empty heredoc name", "<<EOF\nvalue\nEOF\n"},
+		{"empty delimiter", "NAME<<\nvalue\n"},
+		{"unterminated heredoc", "NAME<<EOF\nvalue\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := ParseFileCommand(tt.content); err == nil {
+				t.Errorf("ParseFileCommand(%q) = %q, want an error", tt.content, got)
+			}
+		})
+	}
+}
+
+func TestAddEnvironmentIgnoresProtectedVariables(t *testing.T) {
+	em := testManager(t, nil, nil).ForJob("build", nil)
+
+	ignored := em.AddEnvironment(map[string]string{
+		"MY_VAR":            "1",
+		"GITHUB_SHA":        "forged",
+		"github_workspace":  "/elsewhere",
+		"RUNNER_TEMP":       "/elsewhere",
+		"GITHUB_CUSTOM_VAR": "x",
+	})
+
+	want := []string{"GITHUB_CUSTOM_VAR", "GITHUB_SHA", "RUNNER_TEMP", "github_workspace"}
+	if !reflect.DeepEqual(ignored, want) {
+		t.Errorf("AddEnvironment ignored %q, want %q", ignored, want)
+	}
+
+	env := em.BuildStepEnvironment(nil)
+	if env["MY_VAR"] != "1" {
+		t.Errorf("MY_VAR = %q, want %q", env["MY_VAR"], "1")
+	}
+	if env["GITHUB_SHA"] == "forged" || env["RUNNER_TEMP"] != "/tmp" {
+		t.Errorf("runner variables were overwritten: GITHUB_SHA=%q RUNNER_TEMP=%q", env["GITHUB_SHA"], env["RUNNER_TEMP"])
+	}
+	if _, exists := env["GITHUB_CUSTOM_VAR"]; exists {
+		t.Errorf("GITHUB_CUSTOM_VAR was set through GITHUB_ENV")
+	}
+}
+
+func TestBuildStepEnvironmentPrecedence(t *testing.T) {
+	workflowEnv := map[string]string{"FROM_WORKFLOW": "workflow", "SHARED": "workflow", "CI": "workflow"}
+	jobEnv := map[string]string{"FROM_JOB": "job", "SHARED": "job", "JOB_ONLY": "job"}
+
+	em := testManager(t, workflowEnv, nil).ForJob("build", jobEnv)
+	em.AddEnvironment(map[string]string{
+		"SHARED":         "github_env", // workflow and job env: win
+		"JOB_ONLY":       "github_env", // job env: wins
+		"FROM_FILE":      "github_env", // nothing else sets it
+		"CI":             "github_env", // workflow env: wins over the built-in too
+		"GITHUB_ACTIONS": "false",      // protected
+	})
+
+	tests := []struct {
+		name    string
+		stepEnv map[string]string
+		want    map[string]string
+	}{
+		{
+			name: "without step env",
+			want: map[string]string{
+				"FROM_WORKFLOW":  "workflow",
+				"FROM_JOB":       "job",
+				"FROM_FILE":      "github_env",
+				"SHARED":         "job",
+				"JOB_ONLY":       "job",
+				"CI":             "workflow",
+				"GITHUB_ACTIONS": "true",
+			},
+		},
+		{
+			name:    "step env wins over everything",
+			stepEnv: map[string]string{"SHARED": "step", "FROM_FILE": "step", "GITHUB_ACTIONS": "step"},
+			want: map[string]string{
+				"SHARED":         "step",
+				"FROM_FILE":      "step",
+				"JOB_ONLY":       "job",
+				"GITHUB_ACTIONS": "step",
+			},
+		},
+		{
+			name:    "env: leaves $VAR to the shell",
+			stepEnv: map[string]string{"DERIVED": "${FROM_FILE}-$SHARED"},
+			want:    map[string]string{"DERIVED": "${FROM_FILE}-$SHARED"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := em.BuildStepEnvironment(tt.stepEnv)
+			for key, want := range tt.want {
+				if env[key] != want {
+					t.Errorf("%s = %q, want %q", key, env[key], want)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildStepEnvironmentPath(t *testing.T) {
+	em := testManager(t, nil, nil).ForJob("build", nil)
+	em.SetSystemPath("/usr/bin:/bin")
+	em.PrependPath("/opt/first")
+	em.PrependPath("/opt/second")
+	em.PrependPath("/opt/first")
+
+	env := em.BuildStepEnvironment(nil)
+	if want := "/opt/first:/opt/second:/usr/bin:/bin"; env["PATH"] != want {
+		t.Errorf("PATH = %q, want %q", env["PATH"], want)
+	}
+}
+
+func testManager(t *testing.T, workflowEnv, jobEnv map[string]string) *EnvironmentManager {
+	t.Helper()
+	em := NewEnvironmentManager(&workflow.WorkflowDefinition{Env: workflowEnv}, t.TempDir())
+	em.SetJobEnvironment(jobEnv)
+	return em
+}
@@ -0,0 +1,62 @@
+package environment
+
+import (
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+// CurrentRef returns the checked-out ref of the project, e.g. refs/heads/main
+func CurrentRef(projectDir string) string {
+	return getGitRef(projectDir)
+}
+
+// DefaultBranch returns the branch origin/HEAD points to, falling back to main
+func DefaultBranch(projectDir string) string {
+	return getGitDefaultBranch(projectDir)
+}
+
+// ChangedFiles lists the files that differ between the merge base of from and HEAD and
+// the working tree, including uncommitted and untracked files, as a push or pull request
+// of the current work would report them
+func ChangedFiles(projectDir, from string) ([]string, error) {
+	mergeBase, err := git(projectDir, "merge-base", from, "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare with %s: %w", from, err)
+	}
+
+	changed, err := git(projectDir, "diff", "--name-only", strings.TrimSpace(mergeBase))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list changed files: %w", err)
+	}
+	untracked, err := git(projectDir, "ls-files", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list untracked files: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	files := []string{}
+	for _, line := range strings.Split(changed+"\n"+untracked, "\n") {
+		if file := strings.TrimSpace(line); file != "" && !seen[file] {
+			seen[file] = true
+			files = append(files, file)
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// git runs a git command in the project and returns its output
+func git(projectDir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = projectDir
+	output, err := cmd.Output()
+	if err != nil {
+		if exitError, ok := err.(*exec.ExitError); ok && len(exitError.Stderr) > 0 {
+			return "", fmt.Errorf("%s", strings.TrimSpace(string(exitError.Stderr)))
+		}
+		return "", err
+	}
+	return string(output), nil
+}
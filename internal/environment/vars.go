@@ -0,0 +1,59 @@
+package environment
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// VarsFile is the project file holding configuration variables for the vars context
+const VarsFile = ".gogh/vars.yml"
+
+// ConfigVariables resolves the vars context. From lowest to highest precedence:
+// the vars: section of .gogh/vars.yml, the environments.<name>: section for the
+// job's environment, then --var flags.
+type ConfigVariables struct {
+	Vars         map[string]string            `yaml:"vars"`         // Repository-wide variables
+	Environments map[string]map[string]string `yaml:"environments"` // Overrides per deployment environment
+
+	overrides map[string]string // From --var, applied last
+}
+
+// LoadConfigVariables reads .gogh/vars.yml from the project, if present, and applies overrides on top
+func LoadConfigVariables(projectDir string, overrides map[string]string) (*ConfigVariables, error) {
+	config := &ConfigVariables{}
+
+	path := filepath.Join(projectDir, VarsFile)
+	content, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if err := yaml.Unmarshal(content, config); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", VarsFile, err)
+		}
+	case !os.IsNotExist(err):
+		return nil, fmt.Errorf("failed to read %s: %w", VarsFile, err)
+	}
+
+	config.overrides = overrides
+	return config, nil
+}
+
+// ForEnvironment returns the vars context for a job targeting the named environment;
+// an empty name gives the variables shared by every job
+func (cv *ConfigVariables) ForEnvironment(name string) map[string]string {
+	vars := make(map[string]string)
+	for key, value := range cv.Vars {
+		vars[key] = value
+	}
+	if name != "" {
+		for key, value := range cv.Environments[name] {
+			vars[key] = value
+		}
+	}
+	for key, value := range cv.overrides {
+		vars[key] = value
+	}
+	return vars
+}
@@ -0,0 +1,97 @@
+package environment
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestConfigVariablesForEnvironment(t *testing.T) {
+	projectDir := t.TempDir()
+	content := `vars:
+  REGION: eu-west-1
+  LOG_LEVEL: info
+  IMAGE: app
+environments:
+  production:
+    REGION: us-east-1
+    REPLICAS: "3"
+  staging:
+    LOG_LEVEL: debug
+`
+	if err := os.MkdirAll(filepath.Join(projectDir, ".gogh"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(projectDir, VarsFile), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := LoadConfigVariables(projectDir, map[string]string{"IMAGE": "app:local", "REPLICAS": "1"})
+	if err != nil {
+		t.Fatalf("LoadConfigVariables() returned error: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		environment string
+		want        map[string]string
+	}{
+		{
+			"repository variables and --var",
+			"",
+			map[string]string{"REGION": "eu-west-1", "LOG_LEVEL": "info", "IMAGE": "app:local", "REPLICAS": "1"},
+		},
+		{
+			"environment overrides repository variables",
+			"staging",
+			map[string]string{"REGION": "eu-west-1", "LOG_LEVEL": "debug", "IMAGE": "app:local", "REPLICAS": "1"},
+		},
+		{
+			"--var overrides the environment",
+			"production",
+			map[string]string{"REGION": "us-east-1", "LOG_LEVEL": "info", "IMAGE": "app:local", "REPLICAS": "1"},
+		},
+		{
+			"unknown environment",
+			"preview",
+			map[string]string{"REGION": "eu-west-1", "LOG_LEVEL": "info", "IMAGE": "app:local", "REPLICAS": "1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := config.ForEnvironment(tt.environment); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ForEnvironment(%q) = %v, want %v", tt.environment, got, tt.want)
+			}
+		})
+	}
+
+	// Each call returns its own map
+	config.ForEnvironment("production")["REGION"] = "changed"
+	if got := config.ForEnvironment("production")["REGION"]; got != "us-east-1" {
+		t.Errorf("ForEnvironment() shares its map, REGION = %q", got)
+	}
+}
+
+func TestLoadConfigVariables(t *testing.T) {
+	config, err := LoadConfigVariables(t.TempDir(), map[string]string{"A": "1"})
+	if err != nil {
+		t.Fatalf("LoadConfigVariables() without the file returned error: %v", err)
+	}
+	if got, want := config.ForEnvironment("production"), map[string]string{"A": "1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ForEnvironment() = %v, want %v", got, want)
+	}
+
+	projectDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(projectDir, ".gogh"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(projectDir, VarsFile), []byte("vars: [a"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadConfigVariables(projectDir, nil); err == nil || !strings.Contains(err.Error(), VarsFile) {
+		t.Errorf("LoadConfigVariables() of an invalid file = %v, want an error naming %s", err, VarsFile)
+	}
+}
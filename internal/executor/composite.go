@@ -0,0 +1,137 @@
+package executor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Neoxs/gogh/internal/actions"
+	"github.com/Neoxs/gogh/internal/expressions"
+	"github.com/Neoxs/gogh/internal/logging"
+	"github.com/Neoxs/gogh/internal/workflow"
+)
+
+// maxCompositeDepth limits composite actions that use other composite actions
+const maxCompositeDepth = 9
+
+// compositeRunner returns how the composite action of a uses: step runs its steps
+func (we *WorkflowExecutor) compositeRunner(je *jobExecution, step workflow.StepDefinition, stepName string, stepEnv map[string]string) actions.CompositeStepRunner {
+	return func(action *actions.CompositeAction, inputs map[string]string, jobLogger *logging.JobLogger) (map[string]string, error) {
+		return we.executeCompositeAction(je, step, stepName, stepEnv, action, inputs)
+	}
+}
+
+// executeCompositeAction runs the steps of a composite action in the job container.
+// The steps get the action's own inputs context and GITHUB_ACTION_PATH, see the
+// outer step's env:, share the job's file commands, and are shown nested under the
+// outer step. The action's outputs are evaluated against its steps once they finish.
+func (we *WorkflowExecutor) executeCompositeAction(je *jobExecution, step workflow.StepDefinition, stepName string, stepEnv map[string]string, action *actions.CompositeAction, inputs map[string]string) (map[string]string, error) {
+	metadata := action.Metadata
+	if len(je.parentSteps) >= maxCompositeDepth {
+		return nil, fmt.Errorf("composite action %s is nested more than %d levels deep", action.Ref, maxCompositeDepth)
+	}
+
+	scope := *je
+	scope.steps = make(map[string]expressions.StepContext)
+	scope.expressionErrors = nil
+	scope.parentSteps = je.stepPath(je.currentIndex)
+	scope.actionPath = action.Path
+	scope.status = jobStatusSuccess
+	if je.ctx.Err() != nil {
+		scope.status = jobStatusCancelled
+	}
+
+	// The outer step's env: applies to every step of the action
+	scope.baseEnv = make(map[string]string, len(je.baseEnv)+len(step.Env)+1)
+	for key, value := range je.baseEnv {
+		scope.baseEnv[key] = value
+	}
+	for key := range step.Env {
+		scope.baseEnv[key] = stepEnv[key]
+	}
+	scope.baseEnv["GITHUB_ACTION_PATH"] = action.Path
+
+	scope.inputs = actionInputs(action.Metadata, inputs)
+
+	// env: values inside the action evaluate against its contexts until it finishes
+	je.envManager.SetExpander(func(key, value string, env map[string]string) string {
+		return we.interpolate(&scope, "env."+key, value, env)
+	})
+	defer je.envManager.SetExpander(func(key, value string, env map[string]string) string {
+		return we.interpolate(je, "env."+key, value, env)
+	})
+
+	previewContext := we.jobEvaluationContext(&scope, stepEnv)
+	names := make([]string, len(metadata.Runs.Steps))
+	for i, innerStep := range metadata.Runs.Steps {
+		names[i] = we.previewStepName(previewContext, innerStep, i)
+	}
+	we.workflowState.SetSubSteps(je.name, scope.parentSteps, names)
+	we.display.UpdateWorkflowState(we.workflowState)
+
+	stepsErr := we.runSteps(&scope, metadata.Runs.Steps)
+
+	// Outputs are evaluated even when a step failed, like job outputs; they belong to the outer step
+	scope.parentSteps = je.parentSteps
+	scope.currentStep = stepName
+	scope.currentIndex = je.currentIndex
+	outputEnv := je.envManager.BuildStepEnvironment(scope.baseEnv)
+	outputs := make(map[string]string, len(metadata.Outputs))
+	for name, output := range metadata.Outputs {
+		outputs[name] = we.interpolate(&scope, "outputs."+name, output.Value, outputEnv)
+	}
+	if err := scope.takeExpressionErrors(); err != nil && stepsErr == nil {
+		stepsErr = err
+	}
+
+	return outputs, stepsErr
+}
+
+// actionInputs builds an action's inputs context; inputs declared in action.yml
+// that were neither given nor have a default are empty strings
+func actionInputs(metadata *actions.ActionMetadata, given map[string]string) map[string]interface{} {
+	inputs := make(map[string]interface{}, len(metadata.Inputs)+len(given))
+	for name := range metadata.Inputs {
+		inputs[name] = ""
+	}
+	for name, value := range given {
+		// Input names are case-insensitive; keep the spelling of action.yml
+		for declared := range metadata.Inputs {
+			if strings.EqualFold(declared, name) {
+				name = declared
+				break
+			}
+		}
+		inputs[name] = value
+	}
+	return inputs
+}
+
+// stepPath locates the step at index in the display, below the composite steps that contain it
+func (je *jobExecution) stepPath(index int) []int {
+	path := make([]int, 0, len(je.parentSteps)+1)
+	path = append(path, je.parentSteps...)
+	return append(path, index)
+}
+
+// currentStepPath returns the path of the step being prepared, or nil outside of steps
+func (je *jobExecution) currentStepPath() []int {
+	if je.currentStep == "" {
+		return nil
+	}
+	return je.stepPath(je.currentIndex)
+}
+
+// scopedEnv adds the env: of the enclosing composite steps to a step's own env:
+func (je *jobExecution) scopedEnv(stepEnv map[string]string) map[string]string {
+	if len(je.baseEnv) == 0 {
+		return stepEnv
+	}
+	env := make(map[string]string, len(je.baseEnv)+len(stepEnv))
+	for key, value := range je.baseEnv {
+		env[key] = value
+	}
+	for key, value := range stepEnv {
+		env[key] = value
+	}
+	return env
+}
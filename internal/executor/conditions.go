@@ -0,0 +1,64 @@
+package executor
+
+import (
+	"fmt"
+
+	"github.com/Neoxs/gogh/internal/display"
+	"github.com/Neoxs/gogh/internal/expressions"
+	"github.com/Neoxs/gogh/internal/workflow"
+)
+
+// shouldRunJob evaluates a job's if: condition before any of its instances start.
+// needsStatus reflects the results of the job's needs, so a job without a condition
+// (an implicit success()) is skipped when a dependency did not succeed.
+func (we *WorkflowExecutor) shouldRunJob(jobID, needsStatus string, needs map[string]expressions.NeedContext) (bool, error) {
+	job := we.workflowDef.Jobs[jobID]
+
+	jobEnvManager := we.envManager.ForJob(jobID, nil)
+	evalContext := we.newEvaluationContext(jobEnvManager.GetGitHubContext(), jobEnvManager.BuildStepEnvironment(nil), nil, needsStatus)
+	evalContext.Needs = needs
+
+	shouldRun, err := expressions.NewExpressionEvaluator(evalContext).EvaluateCondition(job.If)
+	if err != nil {
+		return false, fmt.Errorf("job %s: %w", jobID, err)
+	}
+	return shouldRun, nil
+}
+
+// shouldRunStep evaluates a step's if: condition against the current job status;
+// steps without a condition only run while the job is still succeeding
+func (we *WorkflowExecutor) shouldRunStep(je *jobExecution, step workflow.StepDefinition, stepEnv map[string]string) (bool, error) {
+	evalContext := we.jobEvaluationContext(je, stepEnv)
+	return expressions.NewExpressionEvaluator(evalContext).EvaluateCondition(step.If)
+}
+
+// continueOnError evaluates a step's continue-on-error setting, which may be an expression
+func (we *WorkflowExecutor) continueOnError(je *jobExecution, step workflow.StepDefinition, stepEnv map[string]string) bool {
+	if step.ContinueOnError == "" {
+		return false
+	}
+
+	evaluator := expressions.NewExpressionEvaluator(we.jobEvaluationContext(je, stepEnv))
+	value, err := evaluator.EvaluateValue(step.ContinueOnError)
+	if err != nil {
+		je.logger.LogStepOutput(fmt.Sprintf("Invalid continue-on-error value '%s': %v", step.ContinueOnError, err))
+		return false
+	}
+	return expressions.IsTruthy(value)
+}
+
+// skipStep marks a step whose condition evaluated to false as skipped,
+// or as cancelled when the job itself was cancelled
+func (we *WorkflowExecutor) skipStep(je *jobExecution, stepName string, stepPath []int, condition string) {
+	if condition == "" {
+		condition = "success()"
+	}
+	status := display.StatusSkipped
+	if je.status == jobStatusCancelled {
+		status = display.StatusCancelled
+	}
+
+	je.logger.LogStepSkipped(stepName, condition)
+	we.workflowState.UpdateStepStatusAt(je.name, stepPath, status)
+	we.display.UpdateWorkflowState(we.workflowState)
+}
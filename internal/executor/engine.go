@@ -1,8 +1,12 @@
 package executor
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/Neoxs/gogh/container"
@@ -11,51 +15,189 @@ import (
 	"github.com/Neoxs/gogh/internal/environment"
 	"github.com/Neoxs/gogh/internal/expressions"
 	"github.com/Neoxs/gogh/internal/logging"
+	"github.com/Neoxs/gogh/internal/secrets"
 	"github.com/Neoxs/gogh/internal/workflow"
 )
 
+// ExecutorOptions configures how a workflow is executed
+type ExecutorOptions struct {
+	MaxParallel  int               // Maximum number of jobs running at once (0 = unlimited)
+	MatrixFilter map[string]string // Only run matrix combinations matching these values
+	Secrets      map[string]string // The secrets context; values are masked in logs and the display
+	Vars         map[string]string // Configuration variables from --var, overriding .gogh/vars.yml
+	EventName    string            // Simulated event; defaults to push
+	EventPath    string            // JSON payload for the event; synthesized from git state when empty
+	Inputs       map[string]string // workflow_dispatch inputs from --input
+
+	// Event is the event already loaded from EventName and EventPath, e.g. to match
+	// triggers; it is loaded again when nil
+	Event *environment.Event
+
+	// PromptInput asks for required inputs that were not provided; nil when there is no terminal
+	PromptInput workflow.InputPrompt
+
+	// LenientExpressions logs expression errors as warnings instead of failing the step
+	LenientExpressions bool
+}
+
 // WorkflowExecutor orchestrates the execution of workflows
 type WorkflowExecutor struct {
 	workflowDef    *workflow.WorkflowDefinition
 	projectDir     string
+	options        ExecutorOptions
 	logger         *logging.WorkflowLogger
 	display        *display.TerminalDisplay
 	workflowState  *display.WorkflowState
 	actionResolver *actions.ActionResolver
 	envManager     *environment.EnvironmentManager
+	vars           *environment.ConfigVariables
+	event          *environment.Event
+	inputs         map[string]interface{} // The inputs context
+	masker         *secrets.Masker
 	startTime      time.Time
+
+	ctx          context.Context           // Cancelled when the run is interrupted
+	jobInstances map[string][]*jobInstance // Runnable instances per job ID
+	jobSlots     chan struct{}             // Global --max-parallel limit (nil = unlimited)
+
+	// Reusable workflows run as nested executors sharing the caller's display and logs
+	called *calledWorkflows // Loaded reusable workflows, shared by the whole run
+	prefix string           // Prepended to job names of a called workflow, e.g. "build / "
+}
+
+// jobExecution holds the per-job state threaded through step execution
+type jobExecution struct {
+	ctx        context.Context
+	jobID      string
+	name       string
+	matrix     map[string]interface{}
+	vars       map[string]string                  // The vars context, with the job's environment applied
+	status     string                             // Current job status: success, failure or cancelled
+	steps      map[string]expressions.StepContext // Completed steps with an id
+	needs      map[string]expressions.NeedContext // Results of the jobs this job needs
+	job        workflow.JobDefinition
+	runner     *container.JobRunner
+	logger     *logging.JobLogger
+	envManager *environment.EnvironmentManager
+
+	currentStep      string  // Step being prepared, used to locate expression errors
+	currentIndex     int     // Display row of the current step among its siblings
+	expressionErrors []error // Collected by interpolate until the step checks them
+
+	// Set while running the steps of a composite action
+	inputs      map[string]interface{} // The action's inputs context
+	actionPath  string                 // Action directory in the container, GITHUB_ACTION_PATH
+	parentSteps []int                  // Rows of the enclosing composite steps, for the display
+	baseEnv     map[string]string      // env: of the enclosing composite steps
+
+	posts *postQueue // Post steps of actions, shared with composite actions
 }
 
 // NewWorkflowExecutor creates a new workflow executor with logging and display
-func NewWorkflowExecutor(workflowDef *workflow.WorkflowDefinition, projectDir string) (*WorkflowExecutor, error) {
-	// Create workflow logger
-	logger, err := logging.NewWorkflowLogger(workflowDef.Name, projectDir)
+func NewWorkflowExecutor(workflowDef *workflow.WorkflowDefinition, projectDir string, options ExecutorOptions) (*WorkflowExecutor, error) {
+	return newWorkflowExecutor(workflowDef, projectDir, options, nil)
+}
+
+// sharedOutput is the display, masker and log directory shared by the workflows of a group
+type sharedOutput struct {
+	display *display.TerminalDisplay
+	masker  *secrets.Masker // Masks the secrets of every workflow, since they share the display
+	logPath string          // Directory for this workflow's logs
+}
+
+func newWorkflowExecutor(workflowDef *workflow.WorkflowDefinition, projectDir string, options ExecutorOptions, shared *sharedOutput) (*WorkflowExecutor, error) {
+	// Load configuration variables for the vars context
+	configVars, err := environment.LoadConfigVariables(projectDir, options.Vars)
+	if err != nil {
+		return nil, err
+	}
+
+	// Load or synthesize the payload of the simulated event; each workflow gets its own copy
+	// of a loaded one, since its inputs are stored in the payload
+	var event *environment.Event
+	if options.Event != nil {
+		event = options.Event.Copy()
+	} else if event, err = environment.LoadEvent(options.EventName, options.EventPath, projectDir); err != nil {
+		return nil, err
+	}
+
+	// In a group every --input goes to every workflow, so each one takes those it declares
+	inputs, err := resolveDispatchInputs(workflowDef, event, options, shared != nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create workflow logger and terminal display, unless the group provides them
+	var logger *logging.WorkflowLogger
+	var terminalDisplay *display.TerminalDisplay
+	if shared != nil {
+		logger, err = logging.NewWorkflowLoggerAt(workflowDef.Name, shared.logPath)
+		terminalDisplay = shared.display
+	} else {
+		logger, err = logging.NewWorkflowLogger(workflowDef.Name, projectDir)
+		terminalDisplay = display.NewTerminalDisplay()
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to create workflow logger: %w", err)
 	}
 
-	// Create terminal display
-	terminalDisplay := display.NewTerminalDisplay()
+	// Mask secret values everywhere they could be shown
+	if options.Secrets == nil {
+		options.Secrets = make(map[string]string)
+	}
+	masker := secrets.NewMasker()
+	if shared != nil {
+		masker = shared.masker
+	}
+	for _, value := range options.Secrets {
+		masker.Add(value)
+	}
+	logger.SetMasker(masker)
+	terminalDisplay.SetMasker(masker)
 
 	// Create workflow state for display
 	workflowState := display.NewWorkflowState(workflowDef.Name, logger.GetLogPath())
+	if shared != nil {
+		terminalDisplay.TrackWorkflow(workflowState)
+	}
 
-	// Create action resolver
+	// Create action resolver; .gogh/overrides.yml replaces actions that must not run locally
 	actionResolver := actions.NewActionResolver(projectDir)
+	overrides, err := actions.LoadActionOverrides(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	actionResolver.SetOverrides(overrides)
 
 	// Create environment manager
 	envManager := environment.NewEnvironmentManager(workflowDef, projectDir)
+	envManager.SetEvent(event)
 
-	return &WorkflowExecutor{
+	we := &WorkflowExecutor{
 		workflowDef:    workflowDef,
 		projectDir:     projectDir,
+		options:        options,
 		logger:         logger,
 		display:        terminalDisplay,
 		workflowState:  workflowState,
 		actionResolver: actionResolver,
 		envManager:     envManager,
+		vars:           configVars,
+		event:          event,
+		inputs:         inputs,
+		masker:         masker,
 		startTime:      time.Now(),
-	}, nil
+		ctx:            context.Background(),
+		jobInstances:   make(map[string][]*jobInstance),
+		called:         newCalledWorkflows(),
+	}
+
+	// Outside a running job, env: values can use the workflow-wide contexts
+	envManager.SetExpander(func(key, value string, env map[string]string) string {
+		return we.interpolateWith(we.newEvaluationContext(envManager.GetGitHubContext(), env, nil, jobStatusSuccess), value)
+	})
+
+	return we, nil
 }
 
 // Execute runs the entire workflow
@@ -63,8 +205,19 @@ func (we *WorkflowExecutor) Execute() error {
 	// Ensure cleanup
 	defer we.logger.Close()
 
+	// The first interrupt cancels the run so always() and cancelled() steps can
+	// still clean up; a second interrupt terminates immediately
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	we.ctx = ctx
+
 	// Log and display workflow start
 	we.logger.LogWorkflowStart(we.workflowDef.Name)
+	we.logger.LogEvent(we.event.Name, we.event.Source)
 	we.display.UpdateWorkflowState(we.workflowState)
 
 	// Get execution order
@@ -78,78 +231,149 @@ func (we *WorkflowExecutor) Execute() error {
 	// Log execution plan
 	we.logger.LogExecutionPlan(executionOrder)
 
-	// Initialize job states for display
-	for _, jobID := range executionOrder {
-		jobState := display.NewJobState(jobID)
-
-		// Pre-populate steps for display
-		if job, exists := we.workflowDef.Jobs[jobID]; exists {
-			for i, step := range job.Steps {
-				stepName := step.Name
-				if stepName == "" {
-					stepName = fmt.Sprintf("Step %d", i+1)
-				}
-				jobState.Steps = append(jobState.Steps, display.NewStepState(stepName))
-			}
-		}
+	// Load every reusable workflow the run calls, so bad calls fail before any job starts
+	if err := we.loadCalledWorkflows(we.workflowDef, []string{we.workflowDef.File}); err != nil {
+		we.logger.LogWorkflowError(err)
+		we.display.ShowWorkflowError(we.workflowState, err)
+		return fmt.Errorf("failed to build execution plan: %w", err)
+	}
 
-		we.workflowState.Jobs[jobID] = jobState
+	// Expand matrix jobs into their instances and initialize job states for display
+	if err := we.planJobs(executionOrder); err != nil {
+		we.logger.LogWorkflowError(err)
+		we.display.ShowWorkflowError(we.workflowState, err)
+		return fmt.Errorf("failed to build execution plan: %w", err)
 	}
 
 	// Update display with initial state
 	we.display.UpdateWorkflowState(we.workflowState)
 
-	// Execute jobs in sequence (for MVP - no parallelization yet)
-	for _, jobID := range executionOrder {
-		if err := we.executeJob(jobID); err != nil {
-			we.workflowState.Status = display.StatusFailure
-			we.logger.LogWorkflowError(err)
-			we.display.ShowWorkflowError(we.workflowState, err)
-			return fmt.Errorf("job %s failed: %w", jobID, err)
-		}
+	// Execute jobs as their dependencies complete
+	if _, err := we.runJobs(executionOrder); err != nil {
+		we.workflowState.SetStatus(display.StatusFailure)
+		we.logger.LogWorkflowError(err)
+		we.display.ShowWorkflowError(we.workflowState, err)
+		return fmt.Errorf("workflow failed: %s", we.masker.Mask(err.Error()))
 	}
 
 	// Workflow completed successfully
 	totalDuration := time.Since(we.startTime)
-	we.workflowState.Status = display.StatusSuccess
+	we.workflowState.SetStatus(display.StatusSuccess)
 	we.logger.LogWorkflowComplete(totalDuration)
 	we.display.ShowWorkflowComplete(we.workflowState, totalDuration)
 
 	return nil
 }
 
-// executeJob runs a single job with integrated logging, display, and environment
-func (we *WorkflowExecutor) executeJob(jobID string) error {
+// planJobs expands every job into its instances and adds them to the display
+func (we *WorkflowExecutor) planJobs(executionOrder []string) error {
+	for _, jobID := range executionOrder {
+		instances, err := we.expandJobInstances(jobID)
+		if err != nil {
+			return err
+		}
+		we.jobInstances[jobID] = instances
+		we.addJobStates(jobID, instances)
+	}
+	return nil
+}
+
+// addJobStates adds a display entry, with its steps, for every instance of a job.
+// A job calling a reusable workflow is followed by the jobs of that workflow.
+func (we *WorkflowExecutor) addJobStates(jobID string, instances []*jobInstance) {
+	job := we.workflowDef.Jobs[jobID]
+	for _, instance := range instances {
+		jobState := display.NewJobState(instance.name)
+		if job.Uses != "" {
+			we.workflowState.AddJob(jobState)
+			we.addCalledJobStates(instance)
+			continue
+		}
+
+		// Pre-populate steps for display; names are evaluated again when each step starts
+		evalContext := we.newEvaluationContext(we.envManager.ForJob(jobID, nil).GetGitHubContext(), nil, instance.matrix, jobStatusSuccess)
+		for i, step := range job.Steps {
+			jobState.Steps = append(jobState.Steps, display.NewStepState(we.previewStepName(evalContext, step, i)))
+		}
+
+		we.workflowState.AddJob(jobState)
+	}
+}
+
+// executeJob runs a single job instance with integrated logging, display, and environment
+func (we *WorkflowExecutor) executeJob(ctx context.Context, instance *jobInstance, needs map[string]expressions.NeedContext) error {
+	jobID := instance.jobID
+	jobName := instance.name
 	job, exists := we.workflowDef.Jobs[jobID]
 	if !exists {
 		return fmt.Errorf("job %s not found", jobID)
 	}
+	if job.Uses != "" {
+		return we.executeWorkflowCall(ctx, instance, needs)
+	}
+
+	// A fail-fast cancellation or interrupt may arrive before this instance starts
+	if ctx.Err() != nil {
+		we.workflowState.UpdateJobStatus(jobName, display.StatusCancelled)
+		we.display.UpdateWorkflowState(we.workflowState)
+		return errJobCancelled
+	}
 
 	// Get job logger
-	jobLogger, err := we.logger.GetJobLogger(jobID)
+	jobLogger, err := we.logger.GetJobLogger(jobName)
 	if err != nil {
 		return fmt.Errorf("failed to create job logger: %w", err)
 	}
 
-	// Configure environment manager for this job
-	we.envManager.SetJobEnvironment(job.Env)
+	// Scope the environment manager to this job
+	jobEnvManager := we.envManager.ForJob(jobID, job.Env)
 
 	// Update job status to running
-	we.workflowState.UpdateJobStatus(jobID, display.StatusRunning)
+	we.workflowState.UpdateJobStatus(jobName, display.StatusRunning)
 	we.display.UpdateWorkflowState(we.workflowState)
 
+	// The job's environment and runs-on may depend on the matrix or needs outputs,
+	// e.g. runs-on: ${{ matrix.os }}
+	jobContext := we.newEvaluationContext(jobEnvManager.GetGitHubContext(), nil, instance.matrix, jobStatusSuccess)
+	jobContext.Needs = needs
+
+	var environmentName string
+	var errs []error
+	if job.Environment != nil {
+		environmentName, errs = evaluateString(jobContext, job.Environment.Name)
+	}
+	if err := we.reportExpressionErrors(jobLogger, jobName, "", nil, "environment", errs); err != nil {
+		we.workflowState.UpdateJobStatus(jobName, display.StatusFailure)
+		jobLogger.LogJobError(jobName, err)
+		we.display.UpdateWorkflowState(we.workflowState)
+		return err
+	}
+	jobVars := we.vars.ForEnvironment(environmentName)
+	jobContext.Vars = jobVars
+
+	runsOn, errs := evaluateString(jobContext, job.RunsOn)
+	if err := we.reportExpressionErrors(jobLogger, jobName, "", nil, "runs-on", errs); err != nil {
+		we.workflowState.UpdateJobStatus(jobName, display.StatusFailure)
+		jobLogger.LogJobError(jobName, err)
+		we.display.UpdateWorkflowState(we.workflowState)
+		return err
+	}
+
 	// Log job start
-	jobLogger.LogJobStart(jobID, job.RunsOn)
+	jobLogger.LogJobStart(jobName, runsOn)
+	if environmentName != "" {
+		jobLogger.LogStepOutput(fmt.Sprintf("Environment: %s", environmentName))
+	}
 
 	jobStartTime := time.Now()
 
 	// Create job runner
-	jobRunner := container.NewJobRunner(job.RunsOn, we.projectDir)
+	jobRunner := container.NewJobRunner(runsOn, we.projectDir)
 
 	// Start container
 	if err := jobRunner.Start(); err != nil {
-		we.workflowState.UpdateJobStatus(jobID, display.StatusFailure)
-		jobLogger.LogJobError(jobID, err)
+		we.workflowState.UpdateJobStatus(jobName, display.StatusFailure)
+		jobLogger.LogJobError(jobName, err)
 		we.display.UpdateWorkflowState(we.workflowState)
 		return fmt.Errorf("failed to start job container: %w", err)
 	}
@@ -157,90 +381,335 @@ func (we *WorkflowExecutor) executeJob(jobID string) error {
 	// Log container start
 	jobLogger.LogContainerStart(jobRunner.GetImage(), jobRunner.GetContainerID())
 
+	// GITHUB_PATH entries are prepended to the container's own PATH
+	jobEnvManager.SetSystemPath(jobRunner.SystemPath())
+
 	// Ensure cleanup
 	defer func() {
 		if err := jobRunner.Stop(); err != nil {
-			jobLogger.LogJobError(jobID, fmt.Errorf("failed to stop container: %w", err))
+			jobLogger.LogJobError(jobName, fmt.Errorf("failed to stop container: %w", err))
 		}
 	}()
 
+	// Steps and actions read the event payload from GITHUB_EVENT_PATH
+	if err := we.writeEventFile(jobRunner); err != nil {
+		we.workflowState.UpdateJobStatus(jobName, display.StatusFailure)
+		jobLogger.LogJobError(jobName, err)
+		we.display.UpdateWorkflowState(we.workflowState)
+		return err
+	}
+
+	je := &jobExecution{
+		ctx:        ctx,
+		jobID:      jobID,
+		name:       jobName,
+		matrix:     instance.matrix,
+		vars:       jobVars,
+		status:     jobStatusSuccess,
+		steps:      make(map[string]expressions.StepContext),
+		needs:      needs,
+		job:        job,
+		runner:     jobRunner,
+		logger:     jobLogger,
+		envManager: jobEnvManager,
+		posts:      &postQueue{},
+	}
+
+	// env: values inside the job see the matrix, steps and needs contexts
+	jobEnvManager.SetExpander(func(key, value string, env map[string]string) string {
+		return we.interpolate(je, "env."+key, value, env)
+	})
+
 	// Handle job-level with: inputs if they exist
 	if job.With != nil {
 		jobLogger.LogStepOutput("Job-level inputs:")
-		stepEnvironment := we.envManager.BuildStepEnvironment(nil) // No step-specific env
+		stepEnvironment := je.envManager.BuildStepEnvironment(nil) // No step-specific env
 
 		for key, value := range job.With {
 			rawValue := fmt.Sprintf("%v", value)
-			expandedValue := we.expandInputVariables(rawValue, stepEnvironment)
+			expandedValue := we.interpolate(je, "with."+key, rawValue, stepEnvironment)
 			jobLogger.LogStepOutput(fmt.Sprintf("  %s: %s", key, expandedValue))
 		}
 	}
 
-	// Execute all steps in sequence
-	for i, step := range job.Steps {
-		stepName := step.Name
-		if stepName == "" {
-			stepName = fmt.Sprintf("Step %d", i+1)
+	// Execute all steps in sequence, then the post steps of the actions they used
+	firstError := we.runSteps(je, job.Steps)
+	if err := we.runPostSteps(je); err != nil && firstError == nil {
+		firstError = err
+	}
+
+	jobDuration := time.Since(jobStartTime)
+
+	// Outputs are evaluated even for failed jobs, like on GitHub
+	je.currentStep = ""
+	instance.outputs = we.evaluateJobOutputs(je)
+	if err := je.takeExpressionErrors(); err != nil && firstError == nil {
+		firstError = err
+	}
+
+	switch {
+	case firstError != nil:
+		we.workflowState.UpdateJobStatus(jobName, display.StatusFailure)
+		jobLogger.LogJobError(jobName, firstError)
+		we.display.UpdateWorkflowState(we.workflowState)
+		return firstError
+
+	case je.status == jobStatusCancelled:
+		we.workflowState.UpdateJobStatus(jobName, display.StatusCancelled)
+		jobLogger.LogJobError(jobName, errJobCancelled)
+		we.display.UpdateWorkflowState(we.workflowState)
+		return errJobCancelled
+	}
+
+	// Job completed successfully
+	we.workflowState.UpdateJobStatus(jobName, display.StatusSuccess)
+	jobLogger.LogJobComplete(jobName, jobDuration)
+	we.display.UpdateWorkflowState(we.workflowState)
+
+	return nil
+}
+
+// runSteps runs steps in sequence and returns the error of the first step that failed.
+// After a failure the remaining steps are still visited so that always() and failure()
+// steps get a chance to run. Composite actions run their own steps through it too.
+func (we *WorkflowExecutor) runSteps(je *jobExecution, steps []workflow.StepDefinition) error {
+	var firstError error
+	for i, step := range steps {
+		// Cancellation switches the job status so only always() and cancelled() steps still run
+		if je.ctx.Err() != nil && je.status != jobStatusCancelled {
+			je.status = jobStatusCancelled
+			je.logger.LogStepOutput("Job was cancelled; remaining steps run only if their condition allows it")
+		}
+
+		// Build complete environment for this step
+		// Errors in env: and name: are located by the raw step name until the name is evaluated
+		je.currentStep = step.Name
+		if je.currentStep == "" {
+			je.currentStep = fmt.Sprintf("Step %d", i+1)
+		}
+		je.currentIndex = i
+		stepPath := je.stepPath(i)
+		stepEnv := je.envManager.BuildStepEnvironment(je.scopedEnv(step.Env))
+
+		// The step name may use expressions, including outputs of earlier steps
+		stepName := we.stepDisplayName(je, stepEnv, step, i)
+		we.workflowState.RenameStepAt(je.name, je.parentSteps, i, stepName)
+		je.currentStep = stepName
+
+		// Evaluate the step's if: condition against the current job status
+		shouldRun, conditionErr := we.shouldRunStep(je, step, stepEnv)
+		if conditionErr != nil {
+			conditionErr = &expressionError{File: we.workflowDef.File, Job: je.name, Step: stepName, Key: "if", Err: conditionErr}
+		}
+		if conditionErr == nil && !shouldRun {
+			// Expressions of a step that does not run are never reported
+			je.takeExpressionErrors()
+			we.skipStep(je, stepName, stepPath, step.If)
+			je.recordStep(step, jobStatusSkipped, jobStatusSkipped, nil)
+			continue
 		}
 
 		// Update step status to running
-		we.workflowState.UpdateStepStatus(jobID, stepName, display.StatusRunning)
+		we.workflowState.UpdateStepStatusAt(je.name, stepPath, display.StatusRunning)
 		we.display.UpdateWorkflowState(we.workflowState)
 
 		stepStartTime := time.Now()
 
-		// Build complete environment for this step
-		stepEnv := we.envManager.BuildStepEnvironment(step.Env)
-
 		var stepError error
 		var stepSuccess bool
+		var stepOutputs map[string]string
 
-		// Determine step type and execute with environment
-		if step.Uses != "" {
-			// Handle action step
-			stepSuccess, stepError = we.executeActionStep(step, jobRunner, stepEnv, jobLogger)
-		} else if step.Run != "" {
-			// Handle run step with full environment integration
-			stepSuccess, stepError = we.executeRunStep(step, jobRunner, stepEnv, jobLogger)
+		if conditionErr != nil {
+			stepError = conditionErr
 		} else {
-			stepError = fmt.Errorf("step has neither 'uses' nor 'run' specified")
+			stepOutputs, stepSuccess, stepError = we.executeStep(je, step, stepName, stepEnv)
+		}
+
+		// Errors not checked by the step itself still fail it
+		if err := je.takeExpressionErrors(); err != nil && stepError == nil {
+			stepError = err
 			stepSuccess = false
 		}
 
 		stepDuration := time.Since(stepStartTime)
 
 		if stepError != nil || !stepSuccess {
-			// Step failed
-			we.workflowState.UpdateStepStatus(jobID, stepName, display.StatusFailure)
-			we.workflowState.UpdateJobStatus(jobID, display.StatusFailure)
-
-			exitCode := 1
-			jobLogger.LogStepComplete(stepName, stepDuration, exitCode)
-			jobLogger.LogJobError(jobID, stepError)
+			we.workflowState.UpdateStepStatusAt(je.name, stepPath, display.StatusFailure)
+			if stepError != nil {
+				we.workflowState.SetStepMessageAt(je.name, stepPath, fmt.Sprintf("❗ %v", stepError))
+				je.logger.LogStepError(stepError)
+			}
+			je.logger.LogStepComplete(stepName, stepDuration, 1)
 			we.display.UpdateWorkflowState(we.workflowState)
 
-			return fmt.Errorf("step '%s' failed: %w", stepName, stepError)
+			// continue-on-error keeps the job successful; the failure stays visible through steps.<id>.outcome
+			if we.continueOnError(je, step, stepEnv) {
+				je.logger.LogStepOutput(fmt.Sprintf("Step '%s' failed but continue-on-error is set: %v", stepName, stepError))
+				je.recordStep(step, jobStatusFailure, jobStatusSuccess, stepOutputs)
+				continue
+			}
+			je.recordStep(step, jobStatusFailure, jobStatusFailure, stepOutputs)
+
+			// Step failed; keep going so cleanup steps can run
+
+			if je.status == jobStatusSuccess {
+				je.status = jobStatusFailure
+			}
+			if firstError == nil {
+				firstError = fmt.Errorf("step '%s' failed: %w", stepName, stepError)
+			}
+			continue
 		}
 
 		// Step succeeded
-		we.workflowState.UpdateStepStatus(jobID, stepName, display.StatusSuccess)
-		jobLogger.LogStepComplete(stepName, stepDuration, 0)
+		je.recordStep(step, jobStatusSuccess, jobStatusSuccess, stepOutputs)
+		we.workflowState.UpdateStepStatusAt(je.name, stepPath, display.StatusSuccess)
+		je.logger.LogStepComplete(stepName, stepDuration, 0)
 		we.display.UpdateWorkflowState(we.workflowState)
 	}
 
-	// Job completed successfully
-	jobDuration := time.Since(jobStartTime)
-	we.workflowState.UpdateJobStatus(jobID, display.StatusSuccess)
-	jobLogger.LogJobComplete(jobID, jobDuration)
-	we.display.UpdateWorkflowState(we.workflowState)
+	return firstError
+}
 
-	return nil
+// evaluateJobOutputs resolves the job's outputs: map, typically from steps.<id>.outputs
+func (we *WorkflowExecutor) evaluateJobOutputs(je *jobExecution) map[string]string {
+	if len(je.job.Outputs) == 0 {
+		return nil
+	}
+
+	jobEnv := je.envManager.BuildStepEnvironment(nil)
+	outputs := make(map[string]string, len(je.job.Outputs))
+	for key, value := range je.job.Outputs {
+		outputs[key] = we.interpolate(je, "outputs."+key, value, jobEnv)
+		je.logger.LogStepOutput(fmt.Sprintf("Job output %s=%s", key, outputs[key]))
+	}
+	return outputs
+}
+
+// stepDisplayName returns the name shown for a step with its expressions evaluated,
+// defaulting to its position
+func (we *WorkflowExecutor) stepDisplayName(je *jobExecution, stepEnv map[string]string, step workflow.StepDefinition, index int) string {
+	if step.Name != "" {
+		return we.interpolate(je, "name", step.Name, stepEnv)
+	}
+	return fmt.Sprintf("Step %d", index+1)
 }
 
-// executeActionStep handles uses: steps through the action system
-func (we *WorkflowExecutor) executeActionStep(step workflow.StepDefinition, jobRunner *container.JobRunner, stepEnv map[string]string, jobLogger *logging.JobLogger) (bool, error) {
-	// Build step environment first (needed for input expansion)
-	stepEnvironment := we.envManager.BuildStepEnvironment(step.Env)
+// previewStepName evaluates a step name before its job runs, for the initial display
+func (we *WorkflowExecutor) previewStepName(evalContext *expressions.EvaluationContext, step workflow.StepDefinition, index int) string {
+	if step.Name != "" {
+		return we.interpolateWith(evalContext, step.Name)
+	}
+	return fmt.Sprintf("Step %d", index+1)
+}
+
+// executeStep runs a uses: or run: step with fresh file-command files, then applies
+// what the step wrote to them. It returns the step's outputs, which combine action
+// outputs and GITHUB_OUTPUT.
+func (we *WorkflowExecutor) executeStep(je *jobExecution, step workflow.StepDefinition, stepName string, stepEnv map[string]string) (map[string]string, bool, error) {
+	if step.Uses == "" && step.Run == "" {
+		return nil, false, fmt.Errorf("step has neither 'uses' nor 'run' specified")
+	}
+
+	fileCommands, err := je.runner.PrepareFileCommands()
+	if err != nil {
+		return nil, false, err
+	}
+	for key, value := range fileCommands.Environment() {
+		stepEnv[key] = value
+	}
+
+	outputs := make(map[string]string)
+	var success bool
+	if step.Uses != "" {
+		// Handle action step
+		var actionOutputs map[string]string
+		actionOutputs, success, err = we.executeActionStep(je, step, stepName, stepEnv)
+		for key, value := range actionOutputs {
+			outputs[key] = value
+		}
+	} else {
+		// Handle run step with full environment integration
+		success, err = we.executeRunStep(je, step, stepName, stepEnv)
+	}
+
+	// Like the GitHub runner, file commands are processed even when the step failed
+	fileOutputs, applyErr := we.applyFileCommands(je, stepName, fileCommands)
+	for key, value := range fileOutputs {
+		outputs[key] = value
+	}
+	if applyErr != nil && err == nil {
+		return outputs, false, applyErr
+	}
+
+	return outputs, success, err
+}
+
+// applyFileCommands feeds GITHUB_ENV and GITHUB_PATH back into the job, appends
+// GITHUB_STEP_SUMMARY to the job summary and returns the values written to GITHUB_OUTPUT
+func (we *WorkflowExecutor) applyFileCommands(je *jobExecution, stepName string, files *container.FileCommands) (map[string]string, error) {
+	envContent, err := je.runner.ReadFile(files.Env)
+	if err != nil {
+		return nil, err
+	}
+	envValues, err := environment.ParseFileCommand(envContent)
+	if err != nil {
+		return nil, fmt.Errorf("unable to process file command 'env': %w", err)
+	}
+	for _, name := range je.envManager.AddEnvironment(envValues) {
+		je.logger.LogWarning(fmt.Sprintf("Can't overwrite %s through GITHUB_ENV; it is set by the runner", name))
+	}
+
+	pathContent, err := je.runner.ReadFile(files.Path)
+	if err != nil {
+		return nil, err
+	}
+	for _, dir := range environment.ParsePathFile(pathContent) {
+		je.envManager.PrependPath(dir)
+	}
+
+	summary, err := je.runner.ReadFile(files.StepSummary)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(summary) != "" {
+		if err := je.logger.AppendStepSummary(stepName, summary); err != nil {
+			return nil, err
+		}
+	}
+
+	outputContent, err := je.runner.ReadFile(files.Output)
+	if err != nil {
+		return nil, err
+	}
+	outputs, err := environment.ParseFileCommand(outputContent)
+	if err != nil {
+		return nil, fmt.Errorf("unable to process file command 'output': %w", err)
+	}
+
+	return outputs, nil
+}
+
+// recordStep exposes a finished or skipped step to later expressions as steps.<id>
+func (je *jobExecution) recordStep(step workflow.StepDefinition, outcome, conclusion string, outputs map[string]string) {
+	if step.ID == "" {
+		return
+	}
+	if outputs == nil {
+		outputs = make(map[string]string)
+	}
+
+	je.steps[step.ID] = expressions.StepContext{
+		Outputs:    outputs,
+		Outcome:    outcome,
+		Conclusion: conclusion,
+	}
+}
+
+// executeActionStep handles uses: steps through the action system and returns the action's outputs
+func (we *WorkflowExecutor) executeActionStep(je *jobExecution, step workflow.StepDefinition, stepName string, stepEnv map[string]string) (map[string]string, bool, error) {
+	jobRunner := je.runner
+	jobLogger := je.logger
 
 	// Convert step inputs to string map WITH environment variable expansion
 	inputs := make(map[string]string)
@@ -248,17 +717,23 @@ func (we *WorkflowExecutor) executeActionStep(step workflow.StepDefinition, jobR
 		for key, value := range step.With {
 			rawValue := fmt.Sprintf("%v", value)
 			// Expand environment variables in the input value using expression evaluator
-			expandedValue := we.expandInputVariables(rawValue, stepEnvironment)
+			expandedValue := we.interpolate(je, "with."+key, rawValue, stepEnv)
 			inputs[key] = expandedValue
 		}
 	}
 
+	// Never run an action with inputs whose expressions failed
+	if err := je.takeExpressionErrors(); err != nil {
+		return nil, false, err
+	}
+
 	// Create GitHub context from environment manager
-	githubCtx := we.envManager.GetGitHubContext()
+	githubCtx := je.envManager.GetGitHubContext()
 
 	// Create action context with proper GitHub context
 	actionContext := &actions.ActionContext{
 		ActionRef:    step.Uses,
+		StepID:       step.ID,
 		Inputs:       inputs,
 		WorkspaceDir: "/workspace",
 		ContainerID:  jobRunner.GetContainerID(),
@@ -271,7 +746,7 @@ func (we *WorkflowExecutor) executeActionStep(step workflow.StepDefinition, jobR
 			Actor:      githubCtx.Actor,
 			RunID:      githubCtx.RunID,
 			RunNumber:  githubCtx.RunNumber,
-			Job:        "", // Actions don't need job context
+			Job:        githubCtx.Job,
 			Action:     step.Uses,
 			ActionPath: "",
 		},
@@ -281,56 +756,187 @@ func (we *WorkflowExecutor) executeActionStep(step workflow.StepDefinition, jobR
 			Temp: "/tmp",
 			Tool: "/opt/hostedtoolcache",
 		},
-	}
-
-	// Log the expanded inputs for debugging
-	jobLogger.LogStepOutput("Action inputs:")
-	for key, value := range inputs {
-		jobLogger.LogStepOutput(fmt.Sprintf("  %s: %s", key, value))
+		JobRunner: jobRunner,
+		Env:       stepEnv,
+		RunSteps:  we.compositeRunner(je, step, stepName, stepEnv),
+		EvaluateCondition: func(condition string) (bool, error) {
+			return we.evaluateActionCondition(je, condition)
+		},
 	}
 
 	// Resolve and execute action
 	actionExecutor, err := we.actionResolver.ResolveAction(step.Uses, inputs, actionContext)
 	if err != nil {
 		jobLogger.LogStepOutput(fmt.Sprintf("Failed to resolve action: %v", err))
-		return false, err
+		return nil, false, err
+	}
+
+	// Overridden steps are marked so a local run is never mistaken for the real thing
+	if overridden, ok := actionExecutor.(*actions.OverriddenAction); ok {
+		we.workflowState.SetStepNoteAt(je.name, je.currentStepPath(), "🎭 "+overridden.Description())
+		we.display.UpdateWorkflowState(we.workflowState)
+	}
+
+	// Inputs that were not given fall back to the defaults declared by the action
+	metadata := actionExecutor.GetMetadata()
+	for _, warning := range metadata.InputWarnings(inputs) {
+		jobLogger.LogWarning(warning)
+	}
+	inputs = metadata.ApplyDefaults(inputs, func(name, value string) string {
+		return we.interpolate(je, "inputs."+name, value, stepEnv)
+	})
+	if err := je.takeExpressionErrors(); err != nil {
+		return nil, false, err
+	}
+	actionContext.Inputs = inputs
+
+	// Docker actions evaluate runs.args and runs.env against their own inputs
+	actionContext.Interpolate = func(value string) (string, error) {
+		evalContext := we.jobEvaluationContext(je, stepEnv)
+		evalContext.Inputs = actionInputs(metadata, inputs)
+		result, errs := evaluateString(evalContext, value)
+		return result, we.reportExpressionErrors(jobLogger, je.name, je.currentStep, je.currentStepPath(), "runs", errs)
+	}
+
+	// Log the expanded inputs for debugging
+	jobLogger.LogStepOutput("Action inputs:")
+	for key, value := range inputs {
+		jobLogger.LogStepOutput(fmt.Sprintf("  %s: %s", key, value))
 	}
 
 	// Log action start
-	jobLogger.LogStepStart(step.Name, fmt.Sprintf("uses: %s", step.Uses))
+	jobLogger.LogStepStart(stepName, fmt.Sprintf("uses: %s", step.Uses))
 
 	// Execute action (actions handle their own environment setup internally)
 	result, err := actionExecutor.Execute(actionContext, jobLogger)
+
+	// A post step runs at the end of the job once the main step has started, even if it failed
+	if post, ok := actionExecutor.(actions.PostActionExecutor); ok && post.HasPost() {
+		// The post runs with the step's env: as evaluated now, not against the contexts at the end of the job
+		postEnv := make(map[string]string)
+		for key := range je.scopedEnv(step.Env) {
+			postEnv[key] = stepEnv[key]
+		}
+		je.posts.add(stepName, post, actionContext, postEnv)
+	}
 	if err != nil {
-		return false, err
+		if result != nil {
+			return result.Outputs, false, err
+		}
+		return nil, false, err
 	}
 
 	if !result.Success {
-		return false, result.Error
+		if result.Error == nil {
+			return result.Outputs, false, fmt.Errorf("action %s failed", step.Uses)
+		}
+		return result.Outputs, false, result.Error
 	}
 
-	return true, nil
+	return result.Outputs, true, nil
 }
 
 // executeRunStep handles run: steps with full environment variable support
-func (we *WorkflowExecutor) executeRunStep(step workflow.StepDefinition, jobRunner *container.JobRunner, stepEnv map[string]string, jobLogger *logging.JobLogger) (bool, error) {
+func (we *WorkflowExecutor) executeRunStep(je *jobExecution, step workflow.StepDefinition, stepName string, stepEnv map[string]string) (bool, error) {
+	jobRunner := je.runner
+	jobLogger := je.logger
+
+	// Expressions such as ${{ matrix.node }} are substituted before the script runs
+	command := we.interpolate(je, "run", step.Run, stepEnv)
+	workingDir := we.interpolate(je, "working-directory", step.WorkingDirectory, stepEnv)
+
+	// Never run a script whose expressions failed
+	if err := je.takeExpressionErrors(); err != nil {
+		return false, err
+	}
+
 	// Log step start
-	jobLogger.LogStepStart(step.Name, step.Run)
+	jobLogger.LogStepStart(stepName, command)
 
 	// This is the key integration: pass the complete environment to the container
-	result, err := jobRunner.RunStep(step.Name, step.Run, stepEnv, jobLogger)
-	if err != nil || !result.Success {
+	result, err := jobRunner.RunStep(stepName, workingDir, command, stepEnv, jobLogger)
+	if err != nil {
 		return false, err
 	}
+	if !result.Success {
+		return false, fmt.Errorf("process completed with exit code %d", result.ExitCode)
+	}
 
 	return true, nil
 }
 
-// expandInputVariables expands environment variables in action input values using expression evaluator
-func (we *WorkflowExecutor) expandInputVariables(value string, environment map[string]string) string {
-	// Create evaluation context
-	githubCtx := we.envManager.GetGitHubContext()
-	evalContext := &expressions.EvaluationContext{
+// resolveDispatchInputs validates the workflow_dispatch inputs given in the event payload
+// and with --input, applies defaults, and stores them back in the payload as strings
+// for github.event.inputs. It returns the typed values for the inputs context. With
+// onlyDeclared, inputs the workflow doesn't declare are ignored instead of rejected.
+func resolveDispatchInputs(workflowDef *workflow.WorkflowDefinition, event *environment.Event, options ExecutorOptions, onlyDeclared bool) (map[string]interface{}, error) {
+	if event.Name != "workflow_dispatch" {
+		if len(options.Inputs) > 0 {
+			return nil, fmt.Errorf("--input requires the workflow_dispatch event, not %s", event.Name)
+		}
+		return map[string]interface{}{}, nil
+	}
+
+	definitions := workflowDef.DispatchInputs()
+
+	provided := make(map[string]string)
+	if payloadInputs, ok := event.Payload["inputs"].(map[string]interface{}); ok {
+		for name, value := range payloadInputs {
+			provided[name] = expressions.ToString(value)
+		}
+	}
+	for name, value := range options.Inputs {
+		provided[name] = value
+	}
+	if onlyDeclared {
+		for name := range provided {
+			if _, declared := definitions[name]; !declared {
+				delete(provided, name)
+			}
+		}
+	}
+
+	inputs, err := workflow.ResolveInputs(definitions, provided, options.PromptInput)
+	if err != nil {
+		return nil, fmt.Errorf("invalid workflow_dispatch inputs: %w", err)
+	}
+
+	eventInputs := make(map[string]interface{}, len(inputs))
+	for name, value := range inputs {
+		eventInputs[name] = expressions.ToString(value)
+	}
+	event.Payload["inputs"] = eventInputs
+
+	return inputs, nil
+}
+
+// writeEventFile writes the event payload to GITHUB_EVENT_PATH inside a job container
+func (we *WorkflowExecutor) writeEventFile(jobRunner *container.JobRunner) error {
+	payload, err := we.event.JSON()
+	if err != nil {
+		return fmt.Errorf("failed to encode event payload: %w", err)
+	}
+	return jobRunner.WriteFile(environment.EventPath, payload)
+}
+
+// jobEvaluationContext builds the expression contexts for a step of a running job
+func (we *WorkflowExecutor) jobEvaluationContext(je *jobExecution, env map[string]string) *expressions.EvaluationContext {
+	evalContext := we.newEvaluationContext(je.envManager.GetGitHubContext(), env, je.matrix, je.status)
+	evalContext.Vars = je.vars
+	evalContext.Steps = je.steps
+	evalContext.Needs = je.needs
+	if je.actionPath != "" {
+		// Composite actions only see their own inputs, and secrets must be passed as inputs
+		evalContext.Inputs = je.inputs
+		evalContext.Secrets = nil
+		evalContext.Github.ActionPath = je.actionPath
+	}
+	return evalContext
+}
+
+// newEvaluationContext builds the expression contexts for a job
+func (we *WorkflowExecutor) newEvaluationContext(githubCtx environment.GitHubContext, env map[string]string, matrix map[string]interface{}, jobStatus string) *expressions.EvaluationContext {
+	return &expressions.EvaluationContext{
 		Github: expressions.GitHubContext{
 			Repository: githubCtx.Repository,
 			SHA:        githubCtx.SHA,
@@ -340,53 +946,24 @@ func (we *WorkflowExecutor) expandInputVariables(value string, environment map[s
 			RunID:      githubCtx.RunID,
 			RunNumber:  githubCtx.RunNumber,
 			Workspace:  githubCtx.Workspace,
+			Job:        githubCtx.Job,
+			HeadRef:    githubCtx.HeadRef,
+			BaseRef:    githubCtx.BaseRef,
+			Event:      githubCtx.Event,
+			EventPath:  githubCtx.EventPath,
 		},
-		Env: environment,
+		Env:    env,
+		Matrix: matrix,
 		Job: expressions.JobContext{
-			Status: "in_progress", // Could be made dynamic
+			Status: jobStatus,
 		},
 		Runner: expressions.RunnerContext{
 			OS:   "Linux",
 			Arch: "X64",
 		},
-		Secrets: make(map[string]string), // TODO: Add secrets support
+		Secrets:    we.options.Secrets,
+		Vars:       we.vars.ForEnvironment(""),
+		Inputs:     we.inputs,
+		ProjectDir: we.projectDir,
 	}
-
-	// Create evaluator
-	evaluator := expressions.NewExpressionEvaluator(evalContext)
-
-	// Find and replace all ${{ ... }} expressions
-	return we.replaceExpressions(value, evaluator)
-}
-
-// replaceExpressions finds and replaces all expressions in the input string
-func (we *WorkflowExecutor) replaceExpressions(input string, evaluator *expressions.ExpressionEvaluator) string {
-	result := input
-
-	// Simple approach: find ${{ ... }} patterns and evaluate them
-	// This handles multiple expressions in one string like "The ${{ github.event_name }} event triggered this step."
-	for {
-		start := strings.Index(result, "${{")
-		if start == -1 {
-			break
-		}
-
-		end := strings.Index(result[start:], "}}")
-		if end == -1 {
-			break
-		}
-		end = start + end + 2
-
-		expression := result[start:end]
-		evaluated, err := evaluator.Evaluate(expression)
-		if err != nil {
-			// Log error but continue with original expression
-			// In production, you might want better error handling
-			break
-		}
-
-		result = result[:start] + evaluated + result[end:]
-	}
-
-	return result
 }
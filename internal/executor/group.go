@@ -0,0 +1,120 @@
+package executor
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Neoxs/gogh/internal/display"
+	"github.com/Neoxs/gogh/internal/logging"
+	"github.com/Neoxs/gogh/internal/secrets"
+	"github.com/Neoxs/gogh/internal/workflow"
+)
+
+// WorkflowFile is a parsed workflow together with the file it was read from
+type WorkflowFile struct {
+	Path       string
+	Definition *workflow.WorkflowDefinition
+}
+
+// WorkflowGroup runs the workflows triggered by one event together, as GitHub does,
+// sharing a single display, one log root and the --max-parallel limit
+type WorkflowGroup struct {
+	executors []*WorkflowExecutor
+	files     []WorkflowFile
+	display   *display.TerminalDisplay
+	logRoot   string
+	startTime time.Time
+}
+
+// NewWorkflowGroup creates an executor for every workflow; each one logs to a
+// subdirectory of the run's log root named after its file
+func NewWorkflowGroup(files []WorkflowFile, projectDir string, options ExecutorOptions) (*WorkflowGroup, error) {
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no workflows to run")
+	}
+
+	logRoot, err := logging.NewRunLogRoot(projectDir)
+	if err != nil {
+		return nil, err
+	}
+
+	group := &WorkflowGroup{
+		files:     files,
+		display:   display.NewTerminalDisplay(),
+		logRoot:   logRoot,
+		startTime: time.Now(),
+	}
+
+	masker := secrets.NewMasker()
+
+	var jobSlots chan struct{}
+	if options.MaxParallel > 0 {
+		jobSlots = make(chan struct{}, options.MaxParallel)
+	}
+
+	for _, file := range files {
+		stem := strings.TrimSuffix(filepath.Base(file.Path), filepath.Ext(file.Path))
+		shared := &sharedOutput{
+			display: group.display,
+			masker:  masker,
+			logPath: filepath.Join(logRoot, stem),
+		}
+
+		executor, err := newWorkflowExecutor(file.Definition, projectDir, options, shared)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Base(file.Path), err)
+		}
+		executor.jobSlots = jobSlots
+		group.executors = append(group.executors, executor)
+	}
+
+	// Each workflow ignores the inputs it doesn't declare, so catch those no workflow declares
+	for name := range options.Inputs {
+		if !declaresInput(files, name) {
+			return nil, fmt.Errorf("unknown input '%s'; none of the triggered workflows declares it", name)
+		}
+	}
+
+	return group, nil
+}
+
+// declaresInput reports whether any of the workflows declares a workflow_dispatch input
+func declaresInput(files []WorkflowFile, name string) bool {
+	for _, file := range files {
+		if _, declared := file.Definition.DispatchInputs()[name]; declared {
+			return true
+		}
+	}
+	return false
+}
+
+// Execute runs every workflow concurrently and reports which ones failed
+func (wg *WorkflowGroup) Execute() error {
+	errs := make([]error, len(wg.executors))
+	var wait sync.WaitGroup
+	for i, executor := range wg.executors {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			errs[i] = executor.Execute()
+		}()
+	}
+	wait.Wait()
+
+	var failed []string
+	for i, err := range errs {
+		if err != nil {
+			failed = append(failed, filepath.Base(wg.files[i].Path))
+		}
+	}
+
+	wg.display.ShowRunComplete(wg.logRoot, time.Since(wg.startTime), failed)
+
+	if len(failed) > 0 {
+		return fmt.Errorf("%d of %d workflows failed: %s", len(failed), len(wg.executors), strings.Join(failed, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Neoxs/gogh/internal/expressions"
+	"github.com/Neoxs/gogh/internal/logging"
+)
+
+// expressionError reports a failed ${{ }} expression along with where it appears in the workflow
+type expressionError struct {
+	File string // Workflow file
+	Job  string // Job instance name
+	Step string // Step name, empty for job-level keys
+	Key  string // Workflow key, e.g. run, env.VERSION or with.node-version
+	Err  error
+}
+
+func (e *expressionError) Error() string {
+	var location []string
+	if e.File != "" {
+		location = append(location, e.File)
+	}
+	if e.Job != "" {
+		location = append(location, fmt.Sprintf("job '%s'", e.Job))
+	}
+	if e.Step != "" {
+		location = append(location, fmt.Sprintf("step '%s'", e.Step))
+	}
+	if e.Key != "" {
+		location = append(location, e.Key)
+	}
+
+	if len(location) == 0 {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%s: %v", strings.Join(location, ", "), e.Err)
+}
+
+func (e *expressionError) Unwrap() error {
+	return e.Err
+}
+
+// interpolate evaluates every ${{ }} expression in a workflow value within a running job.
+// It is the single path used for run:, name:, env:, with:, working-directory and outputs.
+// Errors are located by key and collected on the job; see takeExpressionErrors.
+func (we *WorkflowExecutor) interpolate(je *jobExecution, key, value string, environment map[string]string) string {
+	result, errs := evaluateString(we.jobEvaluationContext(je, environment), value)
+	if err := we.reportExpressionErrors(je.logger, je.name, je.currentStep, je.currentStepPath(), key, errs); err != nil {
+		je.expressionErrors = append(je.expressionErrors, err)
+	}
+	return result
+}
+
+// interpolateWith evaluates every ${{ }} expression in a value against the given contexts,
+// ignoring errors; used where the value is evaluated again later with full error reporting
+func (we *WorkflowExecutor) interpolateWith(evalContext *expressions.EvaluationContext, value string) string {
+	result, _ := evaluateString(evalContext, value)
+	return result
+}
+
+// reportExpressionErrors locates expression errors. By default they are returned so the
+// step or job fails; with --lenient-expressions they are logged as warnings instead.
+// stepPath locates the step in the display; both are empty for job-level keys.
+func (we *WorkflowExecutor) reportExpressionErrors(jobLogger *logging.JobLogger, jobName, stepName string, stepPath []int, key string, errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	located := make([]error, len(errs))
+	for i, err := range errs {
+		located[i] = &expressionError{File: we.workflowDef.File, Job: jobName, Step: stepName, Key: key, Err: err}
+	}
+
+	if !we.options.LenientExpressions {
+		return errors.Join(located...)
+	}
+
+	for _, err := range located {
+		jobLogger.LogWarning(fmt.Sprintf("%v (replaced with an empty string)", err))
+		if len(stepPath) > 0 {
+			we.workflowState.SetStepMessageAt(jobName, stepPath, fmt.Sprintf("⚠️  %v", err))
+		}
+	}
+	return nil
+}
+
+// takeExpressionErrors returns the expression errors collected since the last call and clears them
+func (je *jobExecution) takeExpressionErrors() error {
+	err := errors.Join(je.expressionErrors...)
+	je.expressionErrors = nil
+	return err
+}
+
+// evaluateString replaces every ${{ }} expression in value. Every expression is evaluated
+// even after a failure; failed expressions become empty strings and their errors are returned.
+func evaluateString(evalContext *expressions.EvaluationContext, value string) (string, []error) {
+	if !strings.Contains(value, "${{") {
+		return value, nil
+	}
+
+	evaluator := expressions.NewExpressionEvaluator(evalContext)
+
+	var builder strings.Builder
+	var errs []error
+	rest := value
+	for {
+		start := strings.Index(rest, "${{")
+		if start == -1 {
+			builder.WriteString(rest)
+			break
+		}
+		builder.WriteString(rest[:start])
+
+		end := expressionEnd(rest, start+3)
+		if end == -1 {
+			errs = append(errs, fmt.Errorf("unclosed expression '%s'", rest[start:]))
+			break
+		}
+
+		evaluated, err := evaluator.Evaluate(rest[start:end])
+		if err != nil {
+			errs = append(errs, err)
+		} else {
+			builder.WriteString(evaluated)
+		}
+		rest = rest[end:]
+	}
+
+	return builder.String(), errs
+}
+
+// expressionEnd returns the index just past the }} closing an expression whose body starts
+// at from, skipping braces inside single-quoted strings; -1 if it is never closed
+func expressionEnd(value string, from int) int {
+	inString := false
+	for i := from; i < len(value); i++ {
+		switch {
+		case value[i] == '\'':
+			inString = !inString // A doubled '' toggles twice and stays inside the string
+		case !inString && strings.HasPrefix(value[i:], "}}"):
+			return i + 2
+		}
+	}
+	return -1
+}
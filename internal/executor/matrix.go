@@ -0,0 +1,108 @@
+package executor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Neoxs/gogh/internal/expressions"
+	"github.com/Neoxs/gogh/internal/workflow"
+)
+
+// jobInstance is a single runnable copy of a job, one per matrix combination
+type jobInstance struct {
+	jobID   string                 // Job ID from the workflow file
+	name    string                 // Display and log name, e.g. "test (18, ubuntu)"
+	matrix  map[string]interface{} // Matrix values for this instance (nil without a matrix)
+	outputs map[string]string      // Job outputs, evaluated when the instance finishes
+}
+
+// expandJobInstances builds the runnable instances of a job from its strategy matrix,
+// applying any --matrix filters from the command line. A matrix written as an expression
+// may depend on needs outputs, so it yields a placeholder until expandMatrixExpression runs.
+func (we *WorkflowExecutor) expandJobInstances(jobID string) ([]*jobInstance, error) {
+	job := we.workflowDef.Jobs[jobID]
+	if job.Strategy == nil || job.Strategy.Matrix.IsEmpty() || job.Strategy.Matrix.Expression != "" {
+		return []*jobInstance{{jobID: jobID, name: we.prefix + jobID}}, nil
+	}
+
+	return we.matrixInstances(jobID, &job.Strategy.Matrix)
+}
+
+// expandMatrixExpression evaluates a matrix expression such as
+// ${{ fromJSON(needs.setup.outputs.matrix) }} once the job's needs have finished
+func (we *WorkflowExecutor) expandMatrixExpression(jobID string, needs map[string]expressions.NeedContext) ([]*jobInstance, error) {
+	job := we.workflowDef.Jobs[jobID]
+
+	jobEnvManager := we.envManager.ForJob(jobID, nil)
+	evalContext := we.newEvaluationContext(jobEnvManager.GetGitHubContext(), jobEnvManager.BuildStepEnvironment(nil), nil, jobStatusSuccess)
+	evalContext.Needs = needs
+
+	value, err := expressions.NewExpressionEvaluator(evalContext).EvaluateValue(job.Strategy.Matrix.Expression)
+	if err != nil {
+		return nil, fmt.Errorf("job %s: %w", jobID, err)
+	}
+
+	matrix, err := workflow.NewMatrixDefinition(value)
+	if err != nil {
+		return nil, fmt.Errorf("job %s: %w", jobID, err)
+	}
+	if matrix.IsEmpty() {
+		return nil, fmt.Errorf("job %s: matrix expression %s produced an empty matrix", jobID, job.Strategy.Matrix.Expression)
+	}
+
+	return we.matrixInstances(jobID, matrix)
+}
+
+// matrixInstances creates one instance per matrix combination
+func (we *WorkflowExecutor) matrixInstances(jobID string, matrix *workflow.MatrixDefinition) ([]*jobInstance, error) {
+	combinations, err := matrix.Expand()
+	if err != nil {
+		return nil, fmt.Errorf("job %s: %w", jobID, err)
+	}
+
+	var instances []*jobInstance
+	for _, combination := range combinations {
+		if !we.matchesMatrixFilter(combination) {
+			continue
+		}
+		instances = append(instances, &jobInstance{
+			jobID:  jobID,
+			name:   fmt.Sprintf("%s%s (%s)", we.prefix, jobID, combination.Name()),
+			matrix: combination.Values,
+		})
+	}
+
+	if len(instances) == 0 {
+		if len(we.options.MatrixFilter) > 0 {
+			return nil, fmt.Errorf("job %s: no matrix combination matches --matrix %s", jobID, formatMatrixFilter(we.options.MatrixFilter))
+		}
+		return nil, fmt.Errorf("job %s: matrix has no combinations after exclude", jobID)
+	}
+
+	return instances, nil
+}
+
+// matchesMatrixFilter reports whether a combination agrees with every --matrix
+// filter for the keys it defines; keys the combination lacks are ignored
+func (we *WorkflowExecutor) matchesMatrixFilter(combination workflow.MatrixCombination) bool {
+	for key, want := range we.options.MatrixFilter {
+		value, exists := combination.Values[key]
+		if !exists {
+			continue
+		}
+		if workflow.FormatMatrixValue(value) != want {
+			return false
+		}
+	}
+	return true
+}
+
+func formatMatrixFilter(filter map[string]string) string {
+	var parts []string
+	for key, value := range filter {
+		parts = append(parts, fmt.Sprintf("%s=%s", key, value))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,133 @@
+package executor
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Neoxs/gogh/internal/actions"
+	"github.com/Neoxs/gogh/internal/display"
+	"github.com/Neoxs/gogh/internal/expressions"
+)
+
+// queuedPost is the post step of an action whose main step ran
+type queuedPost struct {
+	stepName string
+	action   actions.PostActionExecutor
+	ctx      *actions.ActionContext // The main step's context, carrying its inputs and saved state
+	env      map[string]string      // The main step's env:, with the env: of enclosing composite steps
+}
+
+// postQueue collects post steps while a job runs
+type postQueue struct {
+	posts []queuedPost
+}
+
+func (pq *postQueue) add(stepName string, action actions.PostActionExecutor, ctx *actions.ActionContext, env map[string]string) {
+	pq.posts = append(pq.posts, queuedPost{stepName: stepName, action: action, ctx: ctx, env: env})
+}
+
+// runPostSteps runs the queued post steps in reverse order after the main steps, like
+// GitHub does, whatever the job status; each action's post-if, always() by default,
+// decides whether it runs. The post steps are shown as "Post <step name>" rows after
+// the main steps. It returns the error of the first post step that failed.
+func (we *WorkflowExecutor) runPostSteps(je *jobExecution) error {
+	posts := je.posts.posts
+	je.posts.posts = nil
+	if len(posts) == 0 {
+		return nil
+	}
+
+	// Rows are tracked by index, since two actions may well produce the same "Post X" name
+	rows := make([]int, len(posts))
+	for i := len(posts) - 1; i >= 0; i-- {
+		rows[i] = we.workflowState.AddJobStep(je.name, "Post "+posts[i].stepName)
+	}
+	we.display.UpdateWorkflowState(we.workflowState)
+
+	var firstError error
+	for i := len(posts) - 1; i >= 0; i-- {
+		post := posts[i]
+		postName := "Post " + post.stepName
+		je.currentStep = postName
+		je.currentIndex = rows[i]
+		stepPath := je.stepPath(rows[i])
+
+		condition := post.action.GetMetadata().Runs.PostIf
+		if condition == "" {
+			condition = "always()"
+		}
+		shouldRun, err := we.evaluateActionCondition(je, condition)
+		if err != nil {
+			err = &expressionError{File: we.workflowDef.File, Job: je.name, Step: postName, Key: "post-if", Err: err}
+		} else if !shouldRun {
+			we.skipStep(je, postName, stepPath, condition)
+			continue
+		}
+
+		we.workflowState.UpdateStepStatusAt(je.name, stepPath, display.StatusRunning)
+		we.display.UpdateWorkflowState(we.workflowState)
+
+		if err == nil {
+			err = we.executePostStep(je, post, postName)
+		}
+		if err != nil {
+			we.workflowState.UpdateStepStatusAt(je.name, stepPath, display.StatusFailure)
+			we.workflowState.SetStepMessageAt(je.name, stepPath, fmt.Sprintf("❗ %v", err))
+			we.display.UpdateWorkflowState(we.workflowState)
+			if je.status == jobStatusSuccess {
+				je.status = jobStatusFailure
+			}
+			if firstError == nil {
+				firstError = fmt.Errorf("step '%s' failed: %w", postName, err)
+			}
+			continue
+		}
+
+		we.workflowState.UpdateStepStatusAt(je.name, stepPath, display.StatusSuccess)
+		we.display.UpdateWorkflowState(we.workflowState)
+	}
+	return firstError
+}
+
+// executePostStep runs one post step with fresh file commands and the main step's env:;
+// GITHUB_ENV and GITHUB_PATH written by it apply to the posts that follow
+func (we *WorkflowExecutor) executePostStep(je *jobExecution, post queuedPost, postName string) (err error) {
+	startTime := time.Now()
+	je.logger.LogStepStart(postName, fmt.Sprintf("post: %s", post.action.GetName()))
+	defer func() {
+		exitCode := 0
+		if err != nil {
+			je.logger.LogStepError(err)
+			exitCode = 1
+		}
+		je.logger.LogStepComplete(postName, time.Since(startTime), exitCode)
+	}()
+
+	fileCommands, err := je.runner.PrepareFileCommands()
+	if err != nil {
+		return err
+	}
+	env := je.envManager.BuildStepEnvironment(post.env)
+	for key, value := range fileCommands.Environment() {
+		env[key] = value
+	}
+	post.ctx.Env = env
+
+	result, err := post.action.ExecutePost(post.ctx, je.logger)
+	if err == nil && result != nil && !result.Success {
+		err = result.Error
+		if err == nil {
+			err = fmt.Errorf("post of %s failed", post.action.GetName())
+		}
+	}
+	if _, applyErr := we.applyFileCommands(je, postName, fileCommands); applyErr != nil && err == nil {
+		err = applyErr
+	}
+	return err
+}
+
+// evaluateActionCondition evaluates an action's pre-if or post-if against the current job status
+func (we *WorkflowExecutor) evaluateActionCondition(je *jobExecution, condition string) (bool, error) {
+	evalContext := we.jobEvaluationContext(je, je.envManager.BuildStepEnvironment(je.scopedEnv(nil)))
+	return expressions.NewExpressionEvaluator(evalContext).EvaluateCondition(condition)
+}
@@ -0,0 +1,334 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Neoxs/gogh/internal/display"
+	"github.com/Neoxs/gogh/internal/expressions"
+	"github.com/Neoxs/gogh/internal/logging"
+	"github.com/Neoxs/gogh/internal/workflow"
+)
+
+// Limits GitHub enforces on reusable workflows
+const (
+	maxWorkflowDepth   = 4  // The started workflow plus three levels of called workflows
+	maxCalledWorkflows = 20 // Unique reusable workflows a single run can call
+)
+
+// calledJobSeparator names the jobs of a called workflow after the calling job
+const calledJobSeparator = display.CalledJobSeparator
+
+// calledWorkflows holds the reusable workflows a run calls, by their uses: path
+type calledWorkflows struct {
+	workflows map[string]*workflow.WorkflowDefinition
+	mu        sync.Mutex
+}
+
+func newCalledWorkflows() *calledWorkflows {
+	return &calledWorkflows{workflows: make(map[string]*workflow.WorkflowDefinition)}
+}
+
+// load parses a reusable workflow the first time it is called
+func (cw *calledWorkflows) load(projectDir, uses string) (*workflow.WorkflowDefinition, error) {
+	cw.mu.Lock()
+	defer cw.mu.Unlock()
+
+	if !workflow.IsLocalWorkflowRef(uses) {
+		return nil, fmt.Errorf("cannot call '%s': only reusable workflows in this repository (./.github/workflows/...) are supported", uses)
+	}
+	path := filepath.Clean(uses)
+	if calledDef, loaded := cw.workflows[path]; loaded {
+		return calledDef, nil
+	}
+
+	if filepath.Dir(path) != filepath.Join(".github", "workflows") {
+		return nil, fmt.Errorf("cannot call '%s': reusable workflows must be in .github/workflows", uses)
+	}
+	if len(cw.workflows) >= maxCalledWorkflows {
+		return nil, fmt.Errorf("cannot call '%s': a run can call at most %d unique reusable workflows", uses, maxCalledWorkflows)
+	}
+
+	calledDef, err := workflow.NewParser().ParseFile(filepath.Join(projectDir, path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load reusable workflow %s: %w", uses, err)
+	}
+	if _, callable := calledDef.WorkflowCall(); !callable {
+		return nil, fmt.Errorf("%s is not a reusable workflow: it has no on.workflow_call trigger", uses)
+	}
+
+	cw.workflows[path] = calledDef
+	return calledDef, nil
+}
+
+// get returns a reusable workflow loaded by loadCalledWorkflows
+func (cw *calledWorkflows) get(uses string) (*workflow.WorkflowDefinition, error) {
+	cw.mu.Lock()
+	defer cw.mu.Unlock()
+
+	calledDef, loaded := cw.workflows[filepath.Clean(uses)]
+	if !loaded {
+		return nil, fmt.Errorf("reusable workflow %s was not loaded", uses)
+	}
+	return calledDef, nil
+}
+
+// loadCalledWorkflows loads every reusable workflow called by a workflow, recursively, and
+// checks each call against the workflow_call declaration and GitHub's nesting limit.
+// chain lists the workflows from the started one down to workflowDef.
+func (we *WorkflowExecutor) loadCalledWorkflows(workflowDef *workflow.WorkflowDefinition, chain []string) error {
+	jobIDs := make([]string, 0, len(workflowDef.Jobs))
+	for jobID := range workflowDef.Jobs {
+		jobIDs = append(jobIDs, jobID)
+	}
+	sort.Strings(jobIDs)
+
+	for _, jobID := range jobIDs {
+		job := workflowDef.Jobs[jobID]
+		if job.Uses == "" {
+			continue
+		}
+
+		calls := append(append([]string{}, chain...), job.Uses)
+		if len(chain) >= maxWorkflowDepth {
+			return fmt.Errorf("job %s: reusable workflows can be nested at most %d levels deep (%s)", jobID, maxWorkflowDepth, strings.Join(calls, " → "))
+		}
+
+		calledDef, err := we.called.load(we.projectDir, job.Uses)
+		if err != nil {
+			return fmt.Errorf("job %s: %w", jobID, err)
+		}
+		call, _ := calledDef.WorkflowCall()
+		if err := job.ValidateCall(call); err != nil {
+			return fmt.Errorf("job %s calls %s: %w", jobID, job.Uses, err)
+		}
+		if _, err := calledDef.BuildExecutionPlan(); err != nil {
+			return fmt.Errorf("job %s calls %s: %w", jobID, job.Uses, err)
+		}
+
+		if err := we.loadCalledWorkflows(calledDef, calls); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newCalledExecutor creates the executor for a reusable workflow called by a job instance.
+// It shares the run's display, logs, limits and cancellation, and names its jobs
+// after the calling instance.
+func (we *WorkflowExecutor) newCalledExecutor(ctx context.Context, callerName string, calledDef *workflow.WorkflowDefinition, inputs map[string]interface{}, secretValues map[string]string) *WorkflowExecutor {
+	options := we.options
+	options.Secrets = secretValues
+
+	child := &WorkflowExecutor{
+		workflowDef:    calledDef,
+		projectDir:     we.projectDir,
+		options:        options,
+		logger:         we.logger,
+		display:        we.display,
+		workflowState:  we.workflowState,
+		actionResolver: we.actionResolver,
+		envManager:     we.envManager.ForWorkflow(calledDef),
+		vars:           we.vars,
+		event:          we.event,
+		inputs:         inputs,
+		masker:         we.masker,
+		startTime:      time.Now(),
+		ctx:            ctx,
+		jobInstances:   make(map[string][]*jobInstance),
+		jobSlots:       we.jobSlots,
+		called:         we.called,
+		prefix:         callerName + calledJobSeparator,
+	}
+
+	child.envManager.SetExpander(func(key, value string, env map[string]string) string {
+		return child.interpolateWith(child.newEvaluationContext(child.envManager.GetGitHubContext(), env, nil, jobStatusSuccess), value)
+	})
+
+	return child
+}
+
+// addCalledJobStates shows the jobs of the workflow a job instance calls. Their names
+// are previewed without inputs and refreshed when the call starts.
+func (we *WorkflowExecutor) addCalledJobStates(instance *jobInstance) {
+	calledDef, err := we.called.get(we.workflowDef.Jobs[instance.jobID].Uses)
+	if err != nil {
+		return
+	}
+	executionOrder, err := calledDef.BuildExecutionPlan()
+	if err != nil {
+		return
+	}
+
+	preview := we.newCalledExecutor(we.ctx, instance.name, calledDef, map[string]interface{}{}, we.options.Secrets)
+	preview.planJobs(executionOrder) // Errors are reported when the call runs
+}
+
+// executeWorkflowCall runs the reusable workflow a job instance calls. with: and secrets:
+// are evaluated in the calling job's context, the called jobs run as "<caller> / <job>",
+// and the workflow_call outputs become the outputs of the calling job.
+func (we *WorkflowExecutor) executeWorkflowCall(ctx context.Context, instance *jobInstance, needs map[string]expressions.NeedContext) error {
+	jobName := instance.name
+	job := we.workflowDef.Jobs[instance.jobID]
+	calledJobs := jobName + calledJobSeparator
+
+	// A fail-fast cancellation or interrupt may arrive before this instance starts
+	if ctx.Err() != nil {
+		we.workflowState.UpdateJobStatus(jobName, display.StatusCancelled)
+		we.workflowState.UpdatePendingJobs(calledJobs, display.StatusCancelled)
+		we.display.UpdateWorkflowState(we.workflowState)
+		return errJobCancelled
+	}
+
+	jobLogger, err := we.logger.GetJobLogger(jobName)
+	if err != nil {
+		return fmt.Errorf("failed to create job logger: %w", err)
+	}
+
+	we.workflowState.UpdateJobStatus(jobName, display.StatusRunning)
+	we.display.UpdateWorkflowState(we.workflowState)
+
+	fail := func(err error) error {
+		we.workflowState.UpdateJobStatus(jobName, display.StatusFailure)
+		we.workflowState.UpdatePendingJobs(calledJobs, display.StatusSkipped)
+		jobLogger.LogJobError(jobName, err)
+		we.display.UpdateWorkflowState(we.workflowState)
+		return err
+	}
+
+	calledDef, err := we.called.get(job.Uses)
+	if err != nil {
+		return fail(err)
+	}
+	call, _ := calledDef.WorkflowCall()
+	executionOrder, err := calledDef.BuildExecutionPlan()
+	if err != nil {
+		return fail(err)
+	}
+
+	jobEnvManager := we.envManager.ForJob(instance.jobID, nil)
+	callContext := we.newEvaluationContext(jobEnvManager.GetGitHubContext(), jobEnvManager.BuildStepEnvironment(nil), instance.matrix, jobStatusSuccess)
+	callContext.Needs = needs
+
+	provided := make(map[string]string, len(job.With))
+	var errs []error
+	for name, value := range job.With {
+		evaluated, valueErrs := evaluateString(callContext, fmt.Sprintf("%v", value))
+		if err := we.reportExpressionErrors(jobLogger, jobName, "", nil, "with."+name, valueErrs); err != nil {
+			errs = append(errs, err)
+		}
+		provided[name] = evaluated
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fail(err)
+	}
+
+	inputs, err := workflow.ResolveInputs(call.Inputs, provided, nil)
+	if err != nil {
+		return fail(fmt.Errorf("invalid inputs for %s: %w", job.Uses, err))
+	}
+	secretValues, err := we.callSecrets(jobLogger, jobName, job, callContext)
+	if err != nil {
+		return fail(err)
+	}
+
+	we.logger.LogWorkflowCall(jobName, job.Uses, inputs, executionOrder)
+	jobLogger.LogStepOutput(fmt.Sprintf("Calling reusable workflow %s", job.Uses))
+	startTime := time.Now()
+
+	// Replace the previewed jobs now that the inputs are known
+	child := we.newCalledExecutor(ctx, jobName, calledDef, inputs, secretValues)
+	we.workflowState.RemoveJobs(calledJobs)
+	if err := child.planJobs(executionOrder); err != nil {
+		return fail(err)
+	}
+	we.display.UpdateWorkflowState(we.workflowState)
+
+	results, runErr := child.runJobs(executionOrder)
+
+	// Outputs are mapped even when a called job failed, like job outputs
+	outputs, outputErr := child.evaluateCallOutputs(jobLogger, jobName, call, results)
+	instance.outputs = outputs
+
+	switch {
+	case ctx.Err() != nil:
+		we.workflowState.UpdateJobStatus(jobName, display.StatusCancelled)
+		we.workflowState.UpdatePendingJobs(calledJobs, display.StatusCancelled)
+		jobLogger.LogJobError(jobName, errJobCancelled)
+		we.display.UpdateWorkflowState(we.workflowState)
+		return errJobCancelled
+
+	case runErr != nil:
+		return fail(fmt.Errorf("%s: %w", job.Uses, runErr))
+
+	case outputErr != nil:
+		return fail(outputErr)
+	}
+
+	we.workflowState.UpdateJobStatus(jobName, display.StatusSuccess)
+	jobLogger.LogJobComplete(jobName, time.Since(startTime))
+	we.display.UpdateWorkflowState(we.workflowState)
+	return nil
+}
+
+// callSecrets builds the secrets context of a called workflow: every secret of the
+// caller with secrets: inherit, otherwise only the secrets the job passes
+func (we *WorkflowExecutor) callSecrets(jobLogger *logging.JobLogger, jobName string, job workflow.JobDefinition, callContext *expressions.EvaluationContext) (map[string]string, error) {
+	secretValues := make(map[string]string)
+	switch {
+	case job.Secrets == nil:
+		return secretValues, nil
+
+	case job.Secrets.Inherit:
+		for name, value := range we.options.Secrets {
+			secretValues[name] = value
+		}
+		return secretValues, nil
+	}
+
+	var errs []error
+	for name, value := range job.Secrets.Values {
+		evaluated, valueErrs := evaluateString(callContext, value)
+		if err := we.reportExpressionErrors(jobLogger, jobName, "", nil, "secrets."+name, valueErrs); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		// A secret built from an expression is masked like the ones it came from
+		we.masker.Add(evaluated)
+		secretValues[name] = evaluated
+	}
+	return secretValues, errors.Join(errs...)
+}
+
+// evaluateCallOutputs resolves the workflow_call outputs of a called workflow from the
+// results of its jobs, e.g. ${{ jobs.build.outputs.version }}
+func (we *WorkflowExecutor) evaluateCallOutputs(jobLogger *logging.JobLogger, jobName string, call *workflow.EventTrigger, results map[string]jobResult) (map[string]string, error) {
+	if len(call.Outputs) == 0 {
+		return nil, nil
+	}
+
+	jobs := make(map[string]expressions.NeedContext, len(results))
+	for jobID, result := range results {
+		jobs[jobID] = expressions.NeedContext{Outputs: result.outputs, Result: string(result.status)}
+	}
+	evalContext := we.newEvaluationContext(we.envManager.GetGitHubContext(), nil, nil, jobStatusSuccess)
+	evalContext.Jobs = jobs
+
+	outputs := make(map[string]string, len(call.Outputs))
+	var errs []error
+	for name, output := range call.Outputs {
+		value, valueErrs := evaluateString(evalContext, output.Value)
+		if err := we.reportExpressionErrors(jobLogger, jobName, "", nil, "outputs."+name, valueErrs); err != nil {
+			errs = append(errs, err)
+		}
+		outputs[name] = value
+		jobLogger.LogStepOutput(fmt.Sprintf("Workflow output %s=%s", name, value))
+	}
+	return outputs, errors.Join(errs...)
+}
@@ -0,0 +1,278 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Neoxs/gogh/internal/display"
+	"github.com/Neoxs/gogh/internal/expressions"
+)
+
+// Job status values, as seen by job.status, the status check functions and steps.<id>.outcome
+const (
+	jobStatusSuccess   = "success"
+	jobStatusFailure   = "failure"
+	jobStatusCancelled = "cancelled"
+	jobStatusSkipped   = "skipped" // Seen by a job-level if: and as a step outcome
+)
+
+// errJobCancelled is returned by jobs stopped by fail-fast or an interrupt
+var errJobCancelled = errors.New("job was cancelled")
+
+// jobResult is reported by a job goroutine back to the scheduler
+type jobResult struct {
+	jobID   string
+	status  display.ExecutionStatus
+	outputs map[string]string
+	err     error
+}
+
+// runJobs starts each job as soon as all of its needs have finished,
+// running independent jobs and matrix instances concurrently up to the configured limit.
+// Whether a job runs after its needs failed is decided by its if: condition.
+// It returns the result of every job, which a called workflow's outputs are read from.
+func (we *WorkflowExecutor) runJobs(executionOrder []string) (map[string]jobResult, error) {
+	pending := make(map[string]bool, len(executionOrder))
+	for _, jobID := range executionOrder {
+		pending[jobID] = true
+	}
+
+	// A nil channel means no limit on concurrent jobs; a workflow group shares one channel
+	if we.jobSlots == nil && we.options.MaxParallel > 0 {
+		we.jobSlots = make(chan struct{}, we.options.MaxParallel)
+	}
+
+	results := make(map[string]jobResult)
+	completions := make(chan jobResult)
+	running := 0
+	var failures []string
+
+	for len(pending) > 0 || running > 0 {
+		for _, jobID := range executionOrder {
+			if !pending[jobID] {
+				continue
+			}
+
+			finished, needsStatus, needs := we.needsState(jobID, results)
+			if !finished {
+				continue
+			}
+			delete(pending, jobID)
+
+			running++
+			go func(id, status string, needs map[string]expressions.NeedContext) {
+				result, outputs, err := we.runJob(id, status, needs)
+				completions <- jobResult{jobID: id, status: result, outputs: outputs, err: err}
+			}(jobID, needsStatus, needs)
+		}
+
+		if running == 0 {
+			if len(pending) > 0 {
+				return results, fmt.Errorf("unable to schedule jobs: %s", strings.Join(sortedKeys(pending), ", "))
+			}
+			break
+		}
+
+		result := <-completions
+		running--
+		results[result.jobID] = result
+		if len(result.outputs) > 0 {
+			we.logger.LogJobOutputs(we.prefix+result.jobID, result.outputs)
+		}
+		if result.err != nil {
+			failures = append(failures, result.err.Error())
+		}
+	}
+
+	if len(failures) > 0 {
+		return results, fmt.Errorf("%d job(s) failed: %s", len(failures), strings.Join(failures, "; "))
+	}
+
+	return results, nil
+}
+
+// runJob evaluates a job's if: condition against the status of its needs, then runs
+// every instance of the job honoring the strategy's max-parallel and fail-fast settings.
+// It returns the job outputs, merged across matrix instances in completion order.
+func (we *WorkflowExecutor) runJob(jobID, needsStatus string, needs map[string]expressions.NeedContext) (display.ExecutionStatus, map[string]string, error) {
+	instances := we.jobInstances[jobID]
+	strategy := we.workflowDef.Jobs[jobID].Strategy
+
+	// Evaluate the job's if: condition once for all instances
+	shouldRun, err := we.shouldRunJob(jobID, needsStatus, needs)
+	if err != nil {
+		we.failJobInstances(instances)
+		return display.StatusFailure, nil, err
+	}
+	if !shouldRun {
+		reason := "a required job did not succeed"
+		if condition := we.workflowDef.Jobs[jobID].If; condition != "" {
+			reason = fmt.Sprintf("condition '%s' evaluated to false", condition)
+		}
+		we.skipJob(jobID, reason)
+		return display.StatusSkipped, nil, nil
+	}
+
+	// A matrix expression can only be expanded now that the needs outputs are known
+	if strategy != nil && strategy.Matrix.Expression != "" {
+		expanded, err := we.expandMatrixExpression(jobID, needs)
+		if err != nil {
+			we.failJobInstances(instances)
+			return display.StatusFailure, nil, err
+		}
+		for _, placeholder := range instances {
+			we.workflowState.RemoveJob(placeholder.name)
+			we.workflowState.RemoveJobs(placeholder.name + calledJobSeparator)
+		}
+		we.addJobStates(jobID, expanded)
+		we.display.UpdateWorkflowState(we.workflowState)
+		instances = expanded
+	}
+
+	limit := len(instances)
+	if strategy != nil && strategy.MaxParallel > 0 && strategy.MaxParallel < limit {
+		limit = strategy.MaxParallel
+	}
+	strategySlots := make(chan struct{}, limit)
+
+	// Cancelling the context stops remaining matrix instances after a failure
+	ctx, cancel := context.WithCancel(we.ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var failures []string
+	cancelled := false
+	outputs := make(map[string]string)
+
+	for _, instance := range instances {
+		wg.Add(1)
+		go func(instance *jobInstance) {
+			defer wg.Done()
+
+			strategySlots <- struct{}{}
+			defer func() { <-strategySlots }()
+
+			// A job calling a reusable workflow takes no slot; its called jobs do
+			if we.workflowDef.Jobs[jobID].Uses == "" {
+				we.acquireJobSlot()
+				defer we.releaseJobSlot()
+			}
+
+			err := we.executeJob(ctx, instance, needs)
+
+			mu.Lock()
+			for key, value := range instance.outputs {
+				if _, exists := outputs[key]; !exists || value != "" {
+					outputs[key] = value
+				}
+			}
+			if err == nil {
+				mu.Unlock()
+				return
+			}
+			if errors.Is(err, errJobCancelled) {
+				cancelled = true
+			} else {
+				failures = append(failures, fmt.Sprintf("%s: %v", instance.name, err))
+			}
+			mu.Unlock()
+
+			if !errors.Is(err, errJobCancelled) && strategy.IsFailFast() {
+				cancel()
+			}
+		}(instance)
+	}
+	wg.Wait()
+
+	switch {
+	case len(failures) > 0:
+		return display.StatusFailure, outputs, fmt.Errorf("%s", strings.Join(failures, "; "))
+	case cancelled:
+		return display.StatusCancelled, outputs, fmt.Errorf("%s: %w", jobID, errJobCancelled)
+	default:
+		return display.StatusSuccess, outputs, nil
+	}
+}
+
+// failJobInstances marks every instance of a job that could not start as failed
+func (we *WorkflowExecutor) failJobInstances(instances []*jobInstance) {
+	for _, instance := range instances {
+		we.workflowState.UpdateJobStatus(instance.name, display.StatusFailure)
+		we.workflowState.UpdatePendingJobs(instance.name+calledJobSeparator, display.StatusSkipped)
+	}
+	we.display.UpdateWorkflowState(we.workflowState)
+}
+
+// acquireJobSlot blocks until the global --max-parallel limit allows another job
+func (we *WorkflowExecutor) acquireJobSlot() {
+	if we.jobSlots != nil {
+		we.jobSlots <- struct{}{}
+	}
+}
+
+// releaseJobSlot frees a slot taken by acquireJobSlot
+func (we *WorkflowExecutor) releaseJobSlot() {
+	if we.jobSlots != nil {
+		<-we.jobSlots
+	}
+}
+
+// needsState reports whether every dependency of a job has finished and, if so,
+// summarizes their results as the job status seen by the job's if: condition
+// along with the needs context the job can read
+func (we *WorkflowExecutor) needsState(jobID string, results map[string]jobResult) (bool, string, map[string]expressions.NeedContext) {
+	status := jobStatusSuccess
+	needs := make(map[string]expressions.NeedContext)
+	for _, need := range we.workflowDef.Jobs[jobID].Needs.ToSlice() {
+		result, done := results[need]
+		if !done {
+			return false, "", nil
+		}
+		needs[need] = expressions.NeedContext{
+			Outputs: result.outputs,
+			Result:  string(result.status),
+		}
+
+		switch result.status {
+		case display.StatusFailure:
+			status = jobStatusFailure
+		case display.StatusCancelled:
+			if status != jobStatusFailure {
+				status = jobStatusCancelled
+			}
+		case display.StatusSkipped:
+			if status == jobStatusSuccess {
+				status = jobStatusSkipped
+			}
+		}
+	}
+
+	if we.ctx.Err() != nil {
+		status = jobStatusCancelled
+	}
+	return true, status, needs
+}
+
+// skipJob marks every instance of a job as skipped in the display and workflow log
+func (we *WorkflowExecutor) skipJob(jobID, reason string) {
+	we.logger.LogJobSkipped(we.prefix+jobID, reason)
+	for _, instance := range we.jobInstances[jobID] {
+		we.workflowState.UpdateJobStatus(instance.name, display.StatusSkipped)
+		we.workflowState.UpdatePendingJobs(instance.name+calledJobSeparator, display.StatusSkipped)
+	}
+	we.display.UpdateWorkflowState(we.workflowState)
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,87 @@
+package expressions
+
+// Node is an element of a parsed expression tree
+type Node interface {
+	node()
+}
+
+// LiteralNode is a null, boolean, number or string literal
+type LiteralNode struct {
+	Value interface{}
+}
+
+// ContextNode names a top-level context such as github or matrix
+type ContextNode struct {
+	Name string
+}
+
+// PropertyNode dereferences a property: github.ref
+type PropertyNode struct {
+	Target   Node
+	Property string
+}
+
+// IndexNode accesses an element or property by value: matrix['node-version'], needs.*[0]
+type IndexNode struct {
+	Target Node
+	Index  Node
+}
+
+// FilterNode is the object filter: github.event.commits.*
+type FilterNode struct {
+	Target Node
+}
+
+// NotNode is the logical negation operator
+type NotNode struct {
+	Operand Node
+}
+
+// BinaryNode is a comparison or logical operator
+type BinaryNode struct {
+	Operator TokenKind
+	Left     Node
+	Right    Node
+}
+
+// FunctionCallNode calls a built-in function
+type FunctionCallNode struct {
+	Name string
+	Args []Node
+}
+
+func (*LiteralNode) node()      {}
+func (*ContextNode) node()      {}
+func (*PropertyNode) node()     {}
+func (*IndexNode) node()        {}
+func (*FilterNode) node()       {}
+func (*NotNode) node()          {}
+func (*BinaryNode) node()       {}
+func (*FunctionCallNode) node() {}
+
+// walkNodes calls visit for node and every node below it
+func walkNodes(node Node, visit func(Node)) {
+	if node == nil {
+		return
+	}
+	visit(node)
+
+	switch n := node.(type) {
+	case *PropertyNode:
+		walkNodes(n.Target, visit)
+	case *IndexNode:
+		walkNodes(n.Target, visit)
+		walkNodes(n.Index, visit)
+	case *FilterNode:
+		walkNodes(n.Target, visit)
+	case *NotNode:
+		walkNodes(n.Operand, visit)
+	case *BinaryNode:
+		walkNodes(n.Left, visit)
+		walkNodes(n.Right, visit)
+	case *FunctionCallNode:
+		for _, arg := range n.Args {
+			walkNodes(arg, visit)
+		}
+	}
+}
@@ -12,7 +12,15 @@ type EvaluationContext struct {
 	Job     JobContext
 	Runner  RunnerContext
 	Secrets map[string]string
-	// Add other contexts as needed (steps, matrix, etc.)
+	Vars    map[string]string      // Configuration variables; referencing a missing one is an error
+	Inputs  map[string]interface{} // Typed workflow_dispatch inputs
+	Matrix  map[string]interface{}
+	Steps   map[string]StepContext // Completed steps by id
+	Needs   map[string]NeedContext // Results of the jobs listed in needs
+	Jobs    map[string]NeedContext // Results of a called workflow's jobs, for its workflow_call outputs
+
+	// ProjectDir is the host directory hashFiles() resolves patterns against
+	ProjectDir string
 }
 
 type GitHubContext struct {
@@ -27,6 +35,10 @@ type GitHubContext struct {
 	Job        string
 	Action     string
 	ActionPath string
+	HeadRef    string
+	BaseRef    string
+	Event      map[string]interface{} // The webhook payload
+	EventPath  string
 }
 
 type JobContext struct {
@@ -34,6 +46,19 @@ type JobContext struct {
 	// ... other job context fields
 }
 
+// StepContext is what later expressions can read about a completed step
+type StepContext struct {
+	Outputs    map[string]string
+	Outcome    string // success, failure, cancelled or skipped, before continue-on-error
+	Conclusion string // The outcome after continue-on-error is applied
+}
+
+// NeedContext is what a job can read about one of the jobs it needs
+type NeedContext struct {
+	Outputs map[string]string
+	Result  string // success, failure, cancelled or skipped
+}
+
 type RunnerContext struct {
 	OS        string
 	Arch      string
@@ -54,73 +79,380 @@ func NewExpressionEvaluator(ctx *EvaluationContext) *ExpressionEvaluator {
 	}
 }
 
-// Evaluate processes a GitHub Actions expression
+// Evaluate processes a ${{ }} expression and returns its value converted to a string
 func (ee *ExpressionEvaluator) Evaluate(expression string) (string, error) {
-	// For now, implement basic ${{ ... }} handling
-	// Later, you can integrate a proper expression parser like actionlint
-
 	if !strings.HasPrefix(expression, "${{") || !strings.HasSuffix(expression, "}}") {
 		return expression, nil // Not an expression
 	}
 
-	// Extract the inner expression
-	inner := strings.TrimSpace(expression[3 : len(expression)-2])
+	value, err := ee.EvaluateValue(expression)
+	if err != nil {
+		return "", err
+	}
+	return ToString(value), nil
+}
+
+// EvaluateValue evaluates an expression, with or without the ${{ }} wrapper, and returns its typed value
+func (ee *ExpressionEvaluator) EvaluateValue(expression string) (interface{}, error) {
+	node, err := Parse(unwrapExpression(expression))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse expression '%s': %w", expression, err)
+	}
 
-	return ee.evaluateExpression(inner)
+	value, err := ee.evaluate(node)
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate expression '%s': %w", expression, err)
+	}
+	return finalizeValue(value), nil
 }
 
-// evaluateExpression handles the core expression evaluation
-func (ee *ExpressionEvaluator) evaluateExpression(expr string) (string, error) {
-	// Handle simple property access for now
-	// This is where you'd integrate a proper parser later
+// EvaluateCondition evaluates an if: condition. Following GitHub's rules the
+// ${{ }} wrapper is optional, and a condition that does not call a status
+// function is implicitly wrapped as success() && (condition).
+func (ee *ExpressionEvaluator) EvaluateCondition(condition string) (bool, error) {
+	condition = unwrapExpression(condition)
+	if condition == "" {
+		condition = "success()"
+	}
+
+	node, err := Parse(condition)
+	if err != nil {
+		return false, fmt.Errorf("invalid condition '%s': %w", condition, err)
+	}
+
+	if !hasStatusFunction(node) {
+		node = &BinaryNode{
+			Operator: TokenAnd,
+			Left:     &FunctionCallNode{Name: "success"},
+			Right:    node,
+		}
+	}
 
-	parts := strings.Split(expr, ".")
-	if len(parts) != 2 {
-		return expr, fmt.Errorf("unsupported expression format: %s", expr)
+	value, err := ee.evaluate(node)
+	if err != nil {
+		return false, fmt.Errorf("invalid condition '%s': %w", condition, err)
 	}
+	return IsTruthy(value), nil
+}
 
-	contextName := strings.ToLower(parts[0])
-	property := parts[1]
+// unwrapExpression strips an optional ${{ }} wrapper and surrounding whitespace
+func unwrapExpression(expression string) string {
+	expression = strings.TrimSpace(expression)
+	if strings.HasPrefix(expression, "${{") && strings.HasSuffix(expression, "}}") {
+		expression = strings.TrimSpace(expression[3 : len(expression)-2])
+	}
+	return expression
+}
 
-	switch contextName {
-	case "github":
-		return ee.getGitHubProperty(property)
-	case "env":
-		if value, exists := ee.context.Env[property]; exists {
-			return value, nil
+// hasStatusFunction reports whether an expression calls one of the job status functions
+func hasStatusFunction(node Node) bool {
+	found := false
+	walkNodes(node, func(n Node) {
+		if call, ok := n.(*FunctionCallNode); ok && isStatusFunction(call.Name) {
+			found = true
 		}
-		return "", fmt.Errorf("environment variable %s not found", property)
-	case "runner":
-		return ee.getRunnerProperty(property)
+	})
+	return found
+}
+
+// evaluate walks the syntax tree and computes a typed value
+func (ee *ExpressionEvaluator) evaluate(node Node) (interface{}, error) {
+	switch n := node.(type) {
+	case *LiteralNode:
+		return n.Value, nil
+
+	case *ContextNode:
+		return ee.lookupContext(n.Name)
+
+	case *PropertyNode:
+		target, err := ee.evaluate(n.Target)
+		if err != nil {
+			return nil, err
+		}
+		if err := ee.checkVariable(n.Target, n.Property); err != nil {
+			return nil, err
+		}
+		return dereference(target, n.Property), nil
+
+	case *IndexNode:
+		target, err := ee.evaluate(n.Target)
+		if err != nil {
+			return nil, err
+		}
+		index, err := ee.evaluate(n.Index)
+		if err != nil {
+			return nil, err
+		}
+		if name, isString := index.(string); isString {
+			if err := ee.checkVariable(n.Target, name); err != nil {
+				return nil, err
+			}
+		}
+		return indexValue(target, index), nil
+
+	case *FilterNode:
+		target, err := ee.evaluate(n.Target)
+		if err != nil {
+			return nil, err
+		}
+		return filterValue(target), nil
+
+	case *NotNode:
+		operand, err := ee.evaluate(n.Operand)
+		if err != nil {
+			return nil, err
+		}
+		return !IsTruthy(operand), nil
+
+	case *BinaryNode:
+		return ee.evaluateBinary(n)
+
+	case *FunctionCallNode:
+		return ee.callFunction(n)
+
 	default:
-		return "", fmt.Errorf("unknown context: %s", contextName)
-	}
-}
-
-func (ee *ExpressionEvaluator) getGitHubProperty(property string) (string, error) {
-	switch property {
-	case "repository":
-		return ee.context.Github.Repository, nil
-	case "sha":
-		return ee.context.Github.SHA, nil
-	case "ref":
-		return ee.context.Github.Ref, nil
-	case "event_name":
-		return ee.context.Github.EventName, nil
-	case "actor":
-		return ee.context.Github.Actor, nil
+		return nil, fmt.Errorf("unsupported expression node %T", node)
+	}
+}
+
+// evaluateBinary handles logical operators, which short-circuit and return an
+// operand rather than a boolean, and comparisons
+func (ee *ExpressionEvaluator) evaluateBinary(n *BinaryNode) (interface{}, error) {
+	left, err := ee.evaluate(n.Left)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.Operator {
+	case TokenAnd:
+		if !IsTruthy(left) {
+			return left, nil
+		}
+		return ee.evaluate(n.Right)
+	case TokenOr:
+		if IsTruthy(left) {
+			return left, nil
+		}
+		return ee.evaluate(n.Right)
+	}
+
+	right, err := ee.evaluate(n.Right)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.Operator {
+	case TokenEqual:
+		return looseEqual(left, right), nil
+	case TokenNotEqual:
+		return !looseEqual(left, right), nil
+	default:
+		return compareValues(n.Operator, left, right), nil
+	}
+}
+
+// lookupContext resolves a top-level named value such as github or env
+func (ee *ExpressionEvaluator) lookupContext(name string) (interface{}, error) {
+	contexts := ee.contexts()
+	if value, exists := contexts[name]; exists {
+		return value, nil
+	}
+	return nil, fmt.Errorf("unrecognized named-value: '%s'", name)
+}
+
+// checkVariable rejects a reference to a configuration variable that was never defined,
+// so a typo in vars.NAME fails loudly instead of becoming an empty string
+func (ee *ExpressionEvaluator) checkVariable(target Node, name string) error {
+	context, isContext := target.(*ContextNode)
+	if !isContext || context.Name != "vars" {
+		return nil
+	}
+
+	vars, _ := normalizeValue(ee.context.Vars).(map[string]interface{})
+	if _, exists := lookupProperty(vars, name); !exists {
+		return fmt.Errorf("unresolved variable 'vars.%s': set it with --var, in .gogh/vars.yml or for the job's environment", name)
+	}
+	return nil
+}
+
+// contexts exposes the evaluation context as expression objects
+func (ee *ExpressionEvaluator) contexts() map[string]interface{} {
+	ctx := ee.context
+	github := ctx.Github
+
+	refName := github.Ref
+	for _, prefix := range []string{"refs/heads/", "refs/tags/", "refs/pull/"} {
+		refName = strings.TrimPrefix(refName, prefix)
+	}
+	refType := "branch"
+	if strings.HasPrefix(github.Ref, "refs/tags/") {
+		refType = "tag"
+	}
+	owner, _, _ := strings.Cut(github.Repository, "/")
+
+	return map[string]interface{}{
+		"github": map[string]interface{}{
+			"repository":       github.Repository,
+			"repository_owner": owner,
+			"sha":              github.SHA,
+			"ref":              github.Ref,
+			"ref_name":         refName,
+			"ref_type":         refType,
+			"workspace":        github.Workspace,
+			"event_name":       github.EventName,
+			"event":            normalizeMap(github.Event),
+			"event_path":       github.EventPath,
+			"head_ref":         github.HeadRef,
+			"base_ref":         github.BaseRef,
+			"actor":            github.Actor,
+			"triggering_actor": github.Actor,
+			"run_id":           github.RunID,
+			"run_number":       github.RunNumber,
+			"run_attempt":      "1",
+			"job":              github.Job,
+			"action":           github.Action,
+			"action_path":      github.ActionPath,
+			"server_url":       "https://github.com",
+			"api_url":          "https://api.github.com",
+			"graphql_url":      "https://api.github.com/graphql",
+		},
+		"env": normalizeValue(ctx.Env),
+		"job": map[string]interface{}{
+			"status": ctx.Job.Status,
+		},
+		"runner": map[string]interface{}{
+			"os":         ctx.Runner.OS,
+			"arch":       ctx.Runner.Arch,
+			"name":       ctx.Runner.Name,
+			"temp":       ctx.Runner.Temp,
+			"tool_cache": ctx.Runner.ToolCache,
+		},
+		"secrets": normalizeValue(ctx.Secrets),
+		"vars":    normalizeValue(ctx.Vars),
+		"inputs":  normalizeMap(ctx.Inputs),
+		"matrix":  normalizeMap(ctx.Matrix),
+		"steps":   stepsContext(ctx.Steps),
+		"needs":   needsContext(ctx.Needs),
+		"jobs":    needsContext(ctx.Jobs),
+	}
+}
+
+// stepsContext converts completed steps into the steps.<id>.* object
+func stepsContext(steps map[string]StepContext) map[string]interface{} {
+	result := make(map[string]interface{}, len(steps))
+	for id, step := range steps {
+		result[id] = map[string]interface{}{
+			"outputs":    normalizeValue(step.Outputs),
+			"outcome":    step.Outcome,
+			"conclusion": step.Conclusion,
+		}
+	}
+	return result
+}
+
+// needsContext converts finished jobs into the needs.<job>.* or jobs.<job>.* object
+func needsContext(needs map[string]NeedContext) map[string]interface{} {
+	result := make(map[string]interface{}, len(needs))
+	for jobID, need := range needs {
+		result[jobID] = map[string]interface{}{
+			"outputs": normalizeValue(need.Outputs),
+			"result":  need.Result,
+		}
+	}
+	return result
+}
+
+// dereference reads a property; missing properties and non-objects yield null
+func dereference(target interface{}, property string) interface{} {
+	switch t := normalizeValue(target).(type) {
+	case map[string]interface{}:
+		value, _ := lookupProperty(t, property)
+		return normalizeValue(value)
+	case filteredArray:
+		// Property access after an object filter applies to every element
+		var result filteredArray
+		for _, item := range t {
+			if object, ok := normalizeValue(item).(map[string]interface{}); ok {
+				if value, exists := lookupProperty(object, property); exists {
+					result = append(result, normalizeValue(value))
+				}
+			}
+		}
+		return result
 	default:
-		return "", fmt.Errorf("unknown github property: %s", property)
+		return nil
 	}
 }
 
-func (ee *ExpressionEvaluator) getRunnerProperty(property string) (string, error) {
-	switch property {
-	case "os":
-		return ee.context.Runner.OS, nil
-	case "arch":
-		return ee.context.Runner.Arch, nil
+// indexValue reads an array element by number or an object property by string
+func indexValue(target, index interface{}) interface{} {
+	switch t := normalizeValue(target).(type) {
+	case []interface{}, filteredArray:
+		items := toSlice(t)
+		position := toNumber(index)
+		if position != position || position < 0 || int(position) >= len(items) {
+			return nil
+		}
+		return normalizeValue(items[int(position)])
+	case map[string]interface{}:
+		return dereference(t, ToString(normalizeValue(index)))
 	default:
-		return "", fmt.Errorf("unknown runner property: %s", property)
+		return nil
+	}
+}
+
+// filterValue applies the .* object filter, producing the values of an object or the elements of an array
+func filterValue(target interface{}) interface{} {
+	switch t := normalizeValue(target).(type) {
+	case []interface{}:
+		return filteredArray(t)
+	case filteredArray:
+		// Flatten nested arrays so chained filters keep working
+		var result filteredArray
+		for _, item := range t {
+			switch inner := normalizeValue(item).(type) {
+			case []interface{}:
+				result = append(result, inner...)
+			case map[string]interface{}:
+				for _, key := range sortedKeys(inner) {
+					result = append(result, inner[key])
+				}
+			}
+		}
+		return result
+	case map[string]interface{}:
+		var result filteredArray
+		for _, key := range sortedKeys(t) {
+			result = append(result, t[key])
+		}
+		return result
+	default:
+		return filteredArray{}
+	}
+}
+
+// finalizeValue converts internal filter results into plain arrays
+func finalizeValue(value interface{}) interface{} {
+	if filtered, ok := value.(filteredArray); ok {
+		return []interface{}(filtered)
+	}
+	return value
+}
+
+func toSlice(value interface{}) []interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		return v
+	case filteredArray:
+		return []interface{}(v)
+	default:
+		return nil
+	}
+}
+
+func normalizeMap(m map[string]interface{}) map[string]interface{} {
+	if m == nil {
+		return map[string]interface{}{}
 	}
+	return m
 }
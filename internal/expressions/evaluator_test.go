@@ -0,0 +1,321 @@
+package expressions
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testContext returns contexts with arrays, objects and mixed-case keys to evaluate against
+func testContext() *EvaluationContext {
+	return &EvaluationContext{
+		Github: GitHubContext{
+			Repository: "acme/app",
+			Ref:        "refs/heads/main",
+			EventName:  "push",
+			Event: map[string]interface{}{
+				"commits": []interface{}{
+					map[string]interface{}{"id": "a1", "message": "first", "author": map[string]interface{}{"name": "Ada"}},
+					map[string]interface{}{"id": "b2", "message": "second", "author": map[string]interface{}{"name": "Bob"}},
+				},
+				"labels": []interface{}{"bug", "urgent"},
+			},
+		},
+		Env:    map[string]string{"Greeting": "hello", "EMPTY": ""},
+		Vars:   map[string]string{"REGION": "eu"},
+		Inputs: map[string]interface{}{"count": float64(3), "dry-run": true},
+		Matrix: map[string]interface{}{
+			"os":     "ubuntu",
+			"node":   float64(20),
+			"config": map[string]interface{}{"name": "debug", "flags": []interface{}{"-v", "-race"}},
+		},
+		Needs: map[string]NeedContext{
+			"build": {Result: "success", Outputs: map[string]string{"version": "1.2.3"}},
+			"lint":  {Result: "failure"},
+		},
+	}
+}
+
+func evaluateValue(t *testing.T, expression string) interface{} {
+	t.Helper()
+	value, err := NewExpressionEvaluator(testContext()).EvaluateValue(expression)
+	if err != nil {
+		t.Fatalf("EvaluateValue(%q): %v", expression, err)
+	}
+	return value
+}
+
+func TestEvaluateValue(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       interface{}
+	}{
+		// Literals
+		{"null", nil},
+		{"true", true},
+		{"'it''s'", "it's"},
+		{"42", 42.0},
+		{"-1.5", -1.5},
+		{".5", 0.5},
+		{"0xff", 255.0},
+		{"-0x10", -16.0},
+		{"0o17", 15.0},
+		{"1e3", 1000.0},
+		{"2.5E-1", 0.25},
+		{"-1e+2", -100.0},
+
+		// Precedence: && binds tighter than ||, ! tighter than comparisons, comparisons tighter than &&
+		{"false && true || true", true},
+		{"true || false && false", true},
+		{"(true || false) && false", false},
+		{"!false && false", false},
+		{"!(false && false)", true},
+		{"1 < 2 == true", true},
+		{"1 == 1 && 2 == 3", false},
+		{"'a' == 'b' || 'c' == 'c'", true},
+
+		// Logical operators return an operand, not a boolean
+		{"'a' && 'b'", "b"},
+		{"'' && 'b'", ""},
+		{"'' || 'x'", "x"},
+		{"0 || null", nil},
+		{"matrix.os || 'default'", "ubuntu"},
+
+		// ! coerces to boolean
+		{"!0", true},
+		{"!''", true},
+		{"!'false'", false},
+		{"!null", true},
+		{"!github.event.labels", false},
+		{"!!'x'", true},
+		{"!matrix.config.flags", false},
+
+		// Property and index access
+		{"github.event.commits[0].message", "first"},
+		{"github.event.commits[1]['author'].name", "Bob"},
+		{"github['event']['labels'][1]", "urgent"},
+		{"github.event.commits[5]", nil},
+		{"github.event.commits[-1]", nil},
+		{"github.event.commits['0'].id", "a1"},
+		{"matrix['os']", "ubuntu"},
+		{"matrix.config.flags[1]", "-race"},
+		{"matrix['config']['name']", "debug"},
+		{"matrix.config.flags[2]", nil},
+		{"matrix.config.missing.deeper", nil},
+		{"github.event.missing.deeper", nil},
+		{"needs.build.outputs.version", "1.2.3"},
+		{"inputs.dry-run", true},
+
+		// Property names are case-insensitive
+		{"env.greeting", "hello"},
+		{"ENV.GREETING", "hello"},
+		{"github.EVENT_NAME", "push"},
+		{"Matrix.OS", "ubuntu"},
+
+		// Object filters
+		{"github.event.commits.*.id", []interface{}{"a1", "b2"}},
+		{"github.event.commits.*.author.name", []interface{}{"Ada", "Bob"}},
+		{"github.event.labels.*", []interface{}{"bug", "urgent"}},
+		{"needs.*.result", []interface{}{"success", "failure"}},
+		{"github.event.commits.*.missing", []interface{}(nil)},
+		{"matrix.config.flags.*", []interface{}{"-v", "-race"}},
+		{"matrix.os.*", []interface{}{}},
+		{"contains(needs.*.result, 'failure')", true},
+		{"contains(github.event.commits.*.id, 'c3')", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			got := evaluateValue(t, test.expression)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("EvaluateValue(%q) = %#v, want %#v", test.expression, got, test.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateComparisons(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       bool
+	}{
+		// == coerces mismatched types to numbers
+		{"1 == '1'", true},
+		{"1 == '1.0'", true},
+		{"0 == ''", true},
+		{"0 == ' '", true},
+		{"null == 0", true},
+		{"null == false", true},
+		{"null == ''", true},
+		{"null == null", true},
+		{"true == 1", true},
+		{"true == '1'", true},
+		{"false == 0", true},
+		{"true == 2", false},
+		{"'abc' == 0", false},
+		{"'0x10' == 16", true},
+		{"'1e2' == 100", true},
+		{"NaN == NaN", false},
+		{"NaN != NaN", true},
+
+		// Strings compare case-insensitively
+		{"'abc' == 'ABC'", true},
+		{"'abc' != 'abd'", true},
+
+		// Arrays and objects only equal themselves
+		{"github.event.labels == github.event.labels", true},
+		{"github.event.labels == 'Array'", false},
+		{"matrix == matrix", true},
+		{"matrix.config == matrix.config", true},
+		{"matrix.config.flags == 'Array'", false},
+
+		// Ordering uses the same coercion; strings compare case-insensitively
+		{"1 < 2", true},
+		{"'10' < 9", false},
+		{"'10' > 9", true},
+		{"'a' < 'B'", true},
+		{"'B' > 'a'", true},
+		{"null < 1", true},
+		{"false < true", true},
+		{"'' <= 0", true},
+		{"2 >= 2", true},
+		{"'abc' < 1", false},
+		{"'abc' > 1", false},
+		{"NaN < 1", false},
+		{"inputs.count > 2", true},
+		{"matrix.node == '20'", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			got := evaluateValue(t, test.expression)
+			if got != test.want {
+				t.Errorf("EvaluateValue(%q) = %#v, want %v", test.expression, got, test.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateString(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       string
+	}{
+		{"${{ 1.50 }}", "1.5"},
+		{"${{ 0xff }}", "255"},
+		{"${{ 1e21 }}", "1000000000000000000000"},
+		{"${{ true }}", "true"},
+		{"${{ null }}", ""},
+		{"${{ github.event.labels }}", "Array"},
+		{"${{ matrix }}", "Object"},
+		{"${{ matrix.config.flags }}", "Array"},
+		{"${{ github.ref_name }}", "main"},
+		{"${{ github.repository_owner }}", "acme"},
+		{"plain text", "plain text"},
+	}
+
+	evaluator := NewExpressionEvaluator(testContext())
+	for _, test := range tests {
+		got, err := evaluator.Evaluate(test.expression)
+		if err != nil {
+			t.Errorf("Evaluate(%q): %v", test.expression, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("Evaluate(%q) = %q, want %q", test.expression, got, test.want)
+		}
+	}
+}
+
+func TestEvaluateCondition(t *testing.T) {
+	tests := []struct {
+		condition string
+		status    string
+		want      bool
+	}{
+		{"", "success", true},
+		{"", "failure", false},
+		{"true", "failure", false}, // Implicit success() &&
+		{"always()", "failure", true},
+		{"failure()", "failure", true},
+		{"failure()", "success", false},
+		{"${{ matrix.os == 'ubuntu' }}", "success", true},
+		{"matrix.os == 'windows'", "success", false},
+		{"failure() || matrix.os == 'ubuntu'", "success", true},
+		{"cancelled()", "cancelled", true},
+		{"success()", "cancelled", false},
+		{"env.EMPTY", "success", false},
+		{"'false'", "success", true}, // A non-empty string is truthy
+	}
+
+	for _, test := range tests {
+		t.Run(test.condition+"/"+test.status, func(t *testing.T) {
+			ctx := testContext()
+			ctx.Job.Status = test.status
+			got, err := NewExpressionEvaluator(ctx).EvaluateCondition(test.condition)
+			if err != nil {
+				t.Fatalf("EvaluateCondition(%q): %v", test.condition, err)
+			}
+			if got != test.want {
+				t.Errorf("EvaluateCondition(%q) with job status %s = %v, want %v", test.condition, test.status, got, test.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateErrors(t *testing.T) {
+	tests := []struct {
+		expression string
+		errorText  string
+	}{
+		{"unknown.value", "unrecognized named-value"},
+		{"vars.MISSING", "unresolved variable 'vars.MISSING'"},
+		{"vars['MISSING']", "unresolved variable 'vars.MISSING'"},
+		{"'unterminated", "unterminated string"},
+		{"1 ==", "end of expression"},
+		{"(true", "end of expression"},
+		{"true true", "unexpected 'true'"},
+		{"a[0", "end of expression"},
+		{"1 = 1", "unexpected character '='"},
+		{"0xzz", "invalid number"},
+		{"nosuchfunction()", "nosuchfunction"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			_, err := NewExpressionEvaluator(testContext()).EvaluateValue(test.expression)
+			if err == nil {
+				t.Fatalf("EvaluateValue(%q) succeeded, want an error", test.expression)
+			}
+			if !strings.Contains(err.Error(), test.errorText) {
+				t.Errorf("EvaluateValue(%q) error = %v, want it to mention %q", test.expression, err, test.errorText)
+			}
+		})
+	}
+}
+
+func TestIsTruthy(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		want  bool
+	}{
+		{nil, false},
+		{false, false},
+		{true, true},
+		{0.0, false},
+		{math.Copysign(0, -1), false},
+		{math.NaN(), false},
+		{-1.0, true},
+		{"", false},
+		{"0", true},
+		{[]interface{}{}, true},
+		{map[string]interface{}{}, true},
+		{3, true},
+	}
+	for _, test := range tests {
+		if got := IsTruthy(test.value); got != test.want {
+			t.Errorf("IsTruthy(%#v) = %v, want %v", test.value, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package expressions
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// expressionFunction implements a built-in function over evaluated arguments
+type expressionFunction struct {
+	minArgs int
+	maxArgs int // -1 for variadic
+	call    func(ee *ExpressionEvaluator, args []interface{}) (interface{}, error)
+}
+
+// builtinFunctions lists the functions available in expressions, keyed by lowercase name
+var builtinFunctions = map[string]expressionFunction{
+	"success":    {0, 0, fnSuccess},
+	"failure":    {0, 0, fnFailure},
+	"cancelled":  {0, 0, fnCancelled},
+	"always":     {0, 0, fnAlways},
+	"contains":   {2, 2, fnContains},
+	"startswith": {2, 2, fnStartsWith},
+	"endswith":   {2, 2, fnEndsWith},
+	"format":     {1, -1, fnFormat},
+	"join":       {1, 2, fnJoin},
+	"tojson":     {1, 1, fnToJSON},
+	"fromjson":   {1, 1, fnFromJSON},
+	"hashfiles":  {1, -1, fnHashFiles},
+}
+
+// fnSuccess is true while no previous step or required job has failed
+func fnSuccess(ee *ExpressionEvaluator, args []interface{}) (interface{}, error) {
+	return ee.jobStatusIs("", "success"), nil
+}
+
+// fnFailure is true once a previous step or required job has failed
+func fnFailure(ee *ExpressionEvaluator, args []interface{}) (interface{}, error) {
+	return ee.jobStatusIs("failure"), nil
+}
+
+// fnCancelled is true when the run was cancelled
+func fnCancelled(ee *ExpressionEvaluator, args []interface{}) (interface{}, error) {
+	return ee.jobStatusIs("cancelled"), nil
+}
+
+// fnAlways is always true
+func fnAlways(ee *ExpressionEvaluator, args []interface{}) (interface{}, error) {
+	return true, nil
+}
+
+// fnContains checks whether an array holds an item, or a string holds a substring,
+// comparing case-insensitively
+func fnContains(ee *ExpressionEvaluator, args []interface{}) (interface{}, error) {
+	search, item := normalizeValue(args[0]), normalizeValue(args[1])
+
+	if items, isArray := search.([]interface{}); isArray {
+		for _, element := range items {
+			if looseEqual(element, item) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	return strings.Contains(strings.ToLower(ToString(search)), strings.ToLower(ToString(item))), nil
+}
+
+// fnStartsWith checks whether a string starts with a value, ignoring case
+func fnStartsWith(ee *ExpressionEvaluator, args []interface{}) (interface{}, error) {
+	return strings.HasPrefix(strings.ToLower(ToString(args[0])), strings.ToLower(ToString(args[1]))), nil
+}
+
+// fnEndsWith checks whether a string ends with a value, ignoring case
+func fnEndsWith(ee *ExpressionEvaluator, args []interface{}) (interface{}, error) {
+	return strings.HasSuffix(strings.ToLower(ToString(args[0])), strings.ToLower(ToString(args[1]))), nil
+}
+
+// fnFormat replaces {N} placeholders with arguments; {{ and }} escape braces
+func fnFormat(ee *ExpressionEvaluator, args []interface{}) (interface{}, error) {
+	format := ToString(args[0])
+	values := args[1:]
+
+	var builder strings.Builder
+	for i := 0; i < len(format); i++ {
+		c := format[i]
+
+		switch {
+		case c == '{' && i+1 < len(format) && format[i+1] == '{':
+			builder.WriteByte('{')
+			i++
+		case c == '}' && i+1 < len(format) && format[i+1] == '}':
+			builder.WriteByte('}')
+			i++
+		case c == '{':
+			end := strings.IndexByte(format[i:], '}')
+			if end == -1 {
+				return nil, fmt.Errorf("format: unclosed '{' in '%s'", format)
+			}
+			index, err := strconv.Atoi(format[i+1 : i+end])
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("format: invalid placeholder '%s' in '%s'", format[i:i+end+1], format)
+			}
+			if index >= len(values) {
+				return nil, fmt.Errorf("format: placeholder {%d} has no matching argument in '%s'", index, format)
+			}
+			builder.WriteString(ToString(normalizeValue(values[index])))
+			i += end
+		case c == '}':
+			return nil, fmt.Errorf("format: unexpected '}' in '%s'", format)
+		default:
+			builder.WriteByte(c)
+		}
+	}
+
+	return builder.String(), nil
+}
+
+// fnJoin concatenates array elements with a separator (default ","); strings pass through unchanged
+func fnJoin(ee *ExpressionEvaluator, args []interface{}) (interface{}, error) {
+	separator := ","
+	if len(args) > 1 {
+		separator = ToString(normalizeValue(args[1]))
+	}
+
+	items, isArray := normalizeValue(args[0]).([]interface{})
+	if !isArray {
+		return ToString(normalizeValue(args[0])), nil
+	}
+
+	parts := make([]string, len(items))
+	for i, item := range items {
+		parts[i] = ToString(normalizeValue(item))
+	}
+	return strings.Join(parts, separator), nil
+}
+
+// fnToJSON returns a pretty-printed JSON representation of a value
+func fnToJSON(ee *ExpressionEvaluator, args []interface{}) (interface{}, error) {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(normalizeValue(args[0])); err != nil {
+		return nil, fmt.Errorf("toJSON: %w", err)
+	}
+	return strings.TrimSuffix(buffer.String(), "\n"), nil
+}
+
+// fnFromJSON parses a JSON string into an expression value
+func fnFromJSON(ee *ExpressionEvaluator, args []interface{}) (interface{}, error) {
+	text := ToString(normalizeValue(args[0]))
+	if strings.TrimSpace(text) == "" {
+		return nil, fmt.Errorf("fromJSON: empty input")
+	}
+
+	var value interface{}
+	if err := json.Unmarshal([]byte(text), &value); err != nil {
+		return nil, fmt.Errorf("fromJSON: %w", err)
+	}
+	return value, nil
+}
+
+// isStatusFunction reports whether name is one of the job status check functions
+func isStatusFunction(name string) bool {
+	switch name {
+	case "success", "failure", "cancelled", "always":
+		return true
+	default:
+		return false
+	}
+}
+
+// callFunction evaluates the arguments and invokes a built-in function
+func (ee *ExpressionEvaluator) callFunction(call *FunctionCallNode) (interface{}, error) {
+	fn, exists := builtinFunctions[call.Name]
+	if !exists {
+		return nil, fmt.Errorf("unrecognized function: '%s'", call.Name)
+	}
+
+	if len(call.Args) < fn.minArgs || (fn.maxArgs >= 0 && len(call.Args) > fn.maxArgs) {
+		return nil, fmt.Errorf("function '%s' called with %d argument(s)", call.Name, len(call.Args))
+	}
+
+	args := make([]interface{}, len(call.Args))
+	for i, arg := range call.Args {
+		value, err := ee.evaluate(arg)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = finalizeValue(value)
+	}
+
+	return fn.call(ee, args)
+}
+
+// jobStatusIs reports whether the current job status is one of the given values
+func (ee *ExpressionEvaluator) jobStatusIs(statuses ...string) bool {
+	for _, status := range statuses {
+		if ee.context.Job.Status == status {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,93 @@
+package expressions
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFunctions(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       interface{}
+	}{
+		// contains searches arrays by loose equality and strings by substring, ignoring case
+		{"contains('Hello world', 'WORLD')", true},
+		{"contains('Hello world', 'planet')", false},
+		{"contains(matrix.config.flags, '-RACE')", true},
+		{"contains(matrix.config.flags, '-ra')", false}, // Array items must match whole
+		{"contains(fromJSON('[1, 2, 3]'), '2')", true},
+		{"contains(fromJSON('[1, 2, 3]'), 4)", false},
+		{"contains(123, 2)", true}, // Non-arrays are compared as strings
+		{"contains('', '')", true},
+		{"startsWith('refs/heads/main', 'REFS/')", true},
+		{"endsWith('release.tar.gz', '.GZ')", true},
+		{"endsWith('release.tar.gz', '.zip')", false},
+
+		// format
+		{"format('{0} {1}', 'a', 'b')", "a b"},
+		{"format('{1}{0}{1}', 'a', 'b')", "bab"},
+		{"format('{{0}}', 'a')", "{0}"},
+		{"format('{{{0}}}', 'a')", "{a}"},
+		{"format('}}{{')", "}{"},
+		{"format('{0}', null)", ""},
+		{"format('{0}', 1.50)", "1.5"},
+		{"format('{0}', true)", "true"},
+		{"format('no placeholders')", "no placeholders"},
+
+		// join
+		{"join(matrix.config.flags)", "-v,-race"},
+		{"join(matrix.config.flags, ' | ')", "-v | -race"},
+		{"join('single', ',')", "single"},
+		{"join(fromJSON('[{\"id\": \"a1\"}, {\"id\": \"b2\"}]').*.id, '+')", "a1+b2"},
+
+		// JSON
+		{"toJSON('x')", `"x"`},
+		{"toJSON(matrix.config.flags)", "[\n  \"-v\",\n  \"-race\"\n]"},
+		{"toJSON(fromJSON('{\"b\":1,\"a\":[true,null]}'))", "{\n  \"a\": [\n    true,\n    null\n  ],\n  \"b\": 1\n}"},
+		{"fromJSON('{\"a\": {\"b\": 2}}').a.b", 2.0},
+		{"fromJSON('[\"x\", \"y\"]')[1]", "y"},
+		{"fromJSON('true')", true},
+		{"fromJSON('null')", nil},
+		{"fromJSON(' 42 ')", 42.0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			got := evaluateValue(t, test.expression)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("EvaluateValue(%q) = %#v, want %#v", test.expression, got, test.want)
+			}
+		})
+	}
+}
+
+func TestFunctionErrors(t *testing.T) {
+	tests := []struct {
+		expression string
+		errorText  string
+	}{
+		{"format('{1}', 'a')", "placeholder {1} has no matching argument"},
+		{"format('{0} {2}', 'a', 'b')", "placeholder {2} has no matching argument"},
+		{"format('{x}', 'a')", "invalid placeholder '{x}'"},
+		{"format('{-1}', 'a')", "invalid placeholder"},
+		{"format('{0', 'a')", "unclosed '{'"},
+		{"format('a}b')", "unexpected '}'"},
+		{"fromJSON('')", "fromJSON: empty input"},
+		{"fromJSON('   ')", "fromJSON: empty input"},
+		{"fromJSON('{not json}')", "fromJSON"},
+		{"fromJSON('[1, 2')", "fromJSON"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			_, err := NewExpressionEvaluator(testContext()).EvaluateValue(test.expression)
+			if err == nil {
+				t.Fatalf("EvaluateValue(%q) succeeded, want an error", test.expression)
+			}
+			if !strings.Contains(err.Error(), test.errorText) {
+				t.Errorf("EvaluateValue(%q) error = %v, want it to mention %q", test.expression, err, test.errorText)
+			}
+		})
+	}
+}
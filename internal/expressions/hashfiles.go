@@ -0,0 +1,157 @@
+package expressions
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// hashFilesIgnoredDirs are local artifacts that would make hashes change between runs:
+// directories with these names anywhere, or at these paths relative to the project
+var (
+	hashFilesIgnoredDirs = map[string]bool{
+		"gogh-logs": true,
+		".git":      true,
+	}
+	hashFilesIgnoredPaths = map[string]bool{
+		".gogh/actions-cache": true,
+	}
+)
+
+// fnHashFiles returns a SHA-256 over every file matching the patterns, relative to the
+// project directory. Patterns prefixed with ! exclude files; no matches yields "".
+func fnHashFiles(ee *ExpressionEvaluator, args []interface{}) (interface{}, error) {
+	root := ee.context.ProjectDir
+	if root == "" {
+		return nil, fmt.Errorf("hashFiles: project directory is not available")
+	}
+
+	var include, exclude []string
+	for _, arg := range args {
+		for _, line := range strings.Split(ToString(normalizeValue(arg)), "\n") {
+			pattern := ee.relativePattern(strings.TrimSpace(line))
+			if pattern == "" {
+				continue
+			}
+			if strings.HasPrefix(pattern, "!") {
+				exclude = append(exclude, ee.relativePattern(pattern[1:]))
+			} else {
+				include = append(include, pattern)
+			}
+		}
+	}
+
+	var files []string
+	err := filepath.WalkDir(root, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		relative, err := filepath.Rel(root, filePath)
+		if err != nil {
+			return err
+		}
+		relative = filepath.ToSlash(relative)
+
+		if entry.IsDir() {
+			if hashFilesIgnoredDirs[entry.Name()] || hashFilesIgnoredPaths[relative] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		if matchesAnyGlob(include, relative) && !matchesAnyGlob(exclude, relative) {
+			files = append(files, filePath)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("hashFiles: %w", err)
+	}
+
+	if len(files) == 0 {
+		return "", nil
+	}
+	sort.Strings(files)
+
+	// Like the GitHub runner, hash the concatenation of each file's own hash
+	combined := sha256.New()
+	for _, file := range files {
+		fileHash, err := hashFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("hashFiles: %w", err)
+		}
+		combined.Write(fileHash)
+	}
+
+	return hex.EncodeToString(combined.Sum(nil)), nil
+}
+
+// relativePattern makes patterns written against the container workspace relative to the project
+func (ee *ExpressionEvaluator) relativePattern(pattern string) string {
+	if workspace := ee.context.Github.Workspace; workspace != "" {
+		pattern = strings.TrimPrefix(pattern, strings.TrimSuffix(workspace, "/")+"/")
+	}
+	return strings.TrimPrefix(pattern, "./")
+}
+
+func hashFile(filePath string) ([]byte, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return nil, err
+	}
+	return hash.Sum(nil), nil
+}
+
+// matchesAnyGlob reports whether a file, or one of its parent directories, matches a pattern
+func matchesAnyGlob(patterns []string, filePath string) bool {
+	segments := strings.Split(filePath, "/")
+	for _, pattern := range patterns {
+		patternSegments := strings.Split(pattern, "/")
+		for depth := len(segments); depth > 0; depth-- {
+			if matchSegments(patternSegments, segments[:depth]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchSegments matches path segments where ** spans any number of directories
+func matchSegments(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+
+	if pattern[0] == "**" {
+		for skip := 0; skip <= len(segments); skip++ {
+			if matchSegments(pattern[1:], segments[skip:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(segments) == 0 {
+		return false
+	}
+	if matched, err := path.Match(pattern[0], segments[0]); err != nil || !matched {
+		return false
+	}
+	return matchSegments(pattern[1:], segments[1:])
+}
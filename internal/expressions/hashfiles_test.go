@@ -0,0 +1,109 @@
+package expressions
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		file := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// expectedHash hashes the concatenation of each file's SHA-256, in the order given
+func expectedHash(contents ...string) string {
+	combined := sha256.New()
+	for _, content := range contents {
+		sum := sha256.Sum256([]byte(content))
+		combined.Write(sum[:])
+	}
+	return hex.EncodeToString(combined.Sum(nil))
+}
+
+func hashFiles(t *testing.T, root string, patterns ...string) string {
+	t.Helper()
+	ctx := &EvaluationContext{ProjectDir: root, Github: GitHubContext{Workspace: "/workspace"}}
+	args := make([]interface{}, len(patterns))
+	for i, pattern := range patterns {
+		args[i] = pattern
+	}
+	value, err := fnHashFiles(NewExpressionEvaluator(ctx), args)
+	if err != nil {
+		t.Fatalf("hashFiles(%v): %v", patterns, err)
+	}
+	return value.(string)
+}
+
+func TestHashFiles(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"go.sum":               "root",
+		"b/go.sum":             "b",
+		"a/go.sum":             "a",
+		"a/deep/nested/go.sum": "nested",
+		"vendor/x/go.sum":      "vendored",
+		"a/main.go":            "package a",
+	})
+
+	// Files are hashed in path order (a/deep/... sorts before a/go.sum), whatever order the patterns name them in
+	sorted := expectedHash("nested", "a", "b", "root", "vendored")
+
+	tests := []struct {
+		name     string
+		patterns []string
+		want     string
+	}{
+		{"recursive", []string{"**/go.sum"}, sorted},
+		{"patterns in another order", []string{"vendor/**/go.sum", "b/go.sum", "**/go.sum"}, sorted},
+		{"single file", []string{"a/go.sum"}, expectedHash("a")},
+		{"workspace path", []string{"/workspace/a/go.sum"}, expectedHash("a")},
+		{"dot slash", []string{"./a/go.sum"}, expectedHash("a")},
+		{"exclusion", []string{"**/go.sum", "!vendor/**"}, expectedHash("nested", "a", "b", "root")},
+		{"directory matches its files", []string{"a/deep"}, expectedHash("nested")},
+		{"single star stays in one directory", []string{"*/go.sum"}, expectedHash("a", "b")},
+		{"multi-line pattern", []string{"a/go.sum\nb/go.sum"}, expectedHash("a", "b")},
+		{"no match", []string{"**/*.lock"}, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := hashFiles(t, root, test.patterns...); got != test.want {
+				t.Errorf("hashFiles(%q) = %s, want %s", test.patterns, got, test.want)
+			}
+		})
+	}
+}
+
+func TestHashFilesSkipsLocalArtifacts(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{"package-lock.json": "lock", "app/package-lock.json": "app"})
+	before := hashFiles(t, root, "**/package-lock.json")
+
+	writeFiles(t, root, map[string]string{
+		".git/package-lock.json":                              "git object",
+		"app/.git/package-lock.json":                          "submodule",
+		"gogh-logs/run-1/package-lock.json":                   "log",
+		".gogh/actions-cache/acme/x/v1/package-lock.json":     "cached action",
+		".gogh/actions-cache/acme/x/v1/sub/package-lock.json": "cached action",
+	})
+	if after := hashFiles(t, root, "**/package-lock.json"); after != before {
+		t.Errorf("hashFiles changed after adding files under .git, gogh-logs and .gogh/actions-cache")
+	}
+
+	// Other files under .gogh still count
+	writeFiles(t, root, map[string]string{".gogh/package-lock.json": "project config"})
+	if after := hashFiles(t, root, "**/package-lock.json"); after == before {
+		t.Errorf("hashFiles ignored .gogh/package-lock.json")
+	}
+}
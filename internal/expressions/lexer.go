@@ -0,0 +1,222 @@
+package expressions
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TokenKind identifies the type of a lexical token
+type TokenKind int
+
+const (
+	TokenEOF TokenKind = iota
+	TokenNull
+	TokenBool
+	TokenNumber
+	TokenString
+	TokenIdentifier
+	TokenDot
+	TokenComma
+	TokenStar
+	TokenLeftParen
+	TokenRightParen
+	TokenLeftBracket
+	TokenRightBracket
+	TokenNot
+	TokenAnd
+	TokenOr
+	TokenEqual
+	TokenNotEqual
+	TokenLess
+	TokenLessEqual
+	TokenGreater
+	TokenGreaterEqual
+)
+
+// Token is a single lexical element of an expression
+type Token struct {
+	Kind  TokenKind
+	Text  string      // Source text of the token
+	Value interface{} // Parsed literal value for null, bool, number and string tokens
+	Pos   int         // Byte offset in the expression
+}
+
+// tokenize splits an expression into tokens following the GitHub Actions grammar
+func tokenize(input string) ([]Token, error) {
+	var tokens []Token
+	pos := 0
+
+	for pos < len(input) {
+		c := input[pos]
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			pos++
+
+		case c == '\'':
+			value, end, err := lexString(input, pos)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, Token{Kind: TokenString, Text: input[pos:end], Value: value, Pos: pos})
+			pos = end
+
+		case isDigit(c) || ((c == '-' || c == '+' || c == '.') && pos+1 < len(input) && isDigit(input[pos+1]) && !followsOperand(tokens)):
+			end := pos + 1
+			for end < len(input) && isNumberChar(input[end]) {
+				end++
+			}
+			text := input[pos:end]
+			value, err := parseNumber(text)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number '%s' at position %d", text, pos)
+			}
+			tokens = append(tokens, Token{Kind: TokenNumber, Text: text, Value: value, Pos: pos})
+			pos = end
+
+		case isIdentifierStart(c):
+			end := pos + 1
+			for end < len(input) && isIdentifierChar(input[end]) {
+				end++
+			}
+			text := input[pos:end]
+			tokens = append(tokens, keywordOrIdentifier(text, pos))
+			pos = end
+
+		default:
+			token, width, err := lexOperator(input, pos)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token)
+			pos += width
+		}
+	}
+
+	tokens = append(tokens, Token{Kind: TokenEOF, Pos: len(input)})
+	return tokens, nil
+}
+
+// lexString reads a single-quoted string, where a doubled quote escapes a quote
+func lexString(input string, start int) (string, int, error) {
+	var builder strings.Builder
+	pos := start + 1
+
+	for pos < len(input) {
+		if input[pos] == '\'' {
+			if pos+1 < len(input) && input[pos+1] == '\'' {
+				builder.WriteByte('\'')
+				pos += 2
+				continue
+			}
+			return builder.String(), pos + 1, nil
+		}
+		builder.WriteByte(input[pos])
+		pos++
+	}
+
+	return "", 0, fmt.Errorf("unterminated string starting at position %d", start)
+}
+
+// lexOperator reads punctuation and operator tokens
+func lexOperator(input string, pos int) (Token, int, error) {
+	twoChar := map[string]TokenKind{
+		"&&": TokenAnd,
+		"||": TokenOr,
+		"==": TokenEqual,
+		"!=": TokenNotEqual,
+		"<=": TokenLessEqual,
+		">=": TokenGreaterEqual,
+	}
+	if pos+1 < len(input) {
+		if kind, exists := twoChar[input[pos:pos+2]]; exists {
+			return Token{Kind: kind, Text: input[pos : pos+2], Pos: pos}, 2, nil
+		}
+	}
+
+	oneChar := map[byte]TokenKind{
+		'.': TokenDot,
+		',': TokenComma,
+		'*': TokenStar,
+		'(': TokenLeftParen,
+		')': TokenRightParen,
+		'[': TokenLeftBracket,
+		']': TokenRightBracket,
+		'!': TokenNot,
+		'<': TokenLess,
+		'>': TokenGreater,
+	}
+	if kind, exists := oneChar[input[pos]]; exists {
+		return Token{Kind: kind, Text: input[pos : pos+1], Pos: pos}, 1, nil
+	}
+
+	return Token{}, 0, fmt.Errorf("unexpected character '%c' at position %d", input[pos], pos)
+}
+
+// keywordOrIdentifier classifies literal keywords; they are case-sensitive like GitHub's
+func keywordOrIdentifier(text string, pos int) Token {
+	switch text {
+	case "null":
+		return Token{Kind: TokenNull, Text: text, Value: nil, Pos: pos}
+	case "true":
+		return Token{Kind: TokenBool, Text: text, Value: true, Pos: pos}
+	case "false":
+		return Token{Kind: TokenBool, Text: text, Value: false, Pos: pos}
+	case "NaN", "Infinity":
+		value, _ := parseNumber(text)
+		return Token{Kind: TokenNumber, Text: text, Value: value, Pos: pos}
+	default:
+		return Token{Kind: TokenIdentifier, Text: text, Pos: pos}
+	}
+}
+
+// followsOperand reports whether the previous token ends an operand, in which case
+// a following '.' is property access rather than the start of a number like .5
+func followsOperand(tokens []Token) bool {
+	if len(tokens) == 0 {
+		return false
+	}
+	switch tokens[len(tokens)-1].Kind {
+	case TokenIdentifier, TokenRightParen, TokenRightBracket, TokenStar:
+		return true
+	default:
+		return false
+	}
+}
+
+// parseNumber parses decimal, hexadecimal, octal and exponent notation
+func parseNumber(text string) (float64, error) {
+	unsigned := strings.TrimLeft(text, "+-")
+	negative := strings.HasPrefix(text, "-")
+
+	if strings.HasPrefix(unsigned, "0x") || strings.HasPrefix(unsigned, "0o") {
+		value, err := strconv.ParseInt(unsigned, 0, 64)
+		if err != nil {
+			return 0, err
+		}
+		if negative {
+			return -float64(value), nil
+		}
+		return float64(value), nil
+	}
+
+	return strconv.ParseFloat(text, 64)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isNumberChar(c byte) bool {
+	return isDigit(c) || c == '.' || c == 'e' || c == 'E' || c == 'x' || c == 'o' ||
+		(c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F') || c == '+' || c == '-'
+}
+
+func isIdentifierStart(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c == '_'
+}
+
+func isIdentifierChar(c byte) bool {
+	return isIdentifierStart(c) || isDigit(c) || c == '-'
+}
@@ -0,0 +1,222 @@
+package expressions
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Parse converts an expression (without the ${{ }} wrapper) into a syntax tree.
+// Operator precedence follows GitHub, from lowest to highest:
+// ||, &&, == !=, < <= > >=, !, then property access, indexing and grouping.
+func Parse(expression string) (Node, error) {
+	tokens, err := tokenize(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.peek().Kind != TokenEOF {
+		token := p.peek()
+		return nil, fmt.Errorf("unexpected '%s' at position %d", token.Text, token.Pos)
+	}
+
+	return node, nil
+}
+
+// parser is a recursive descent parser over a token stream
+type parser struct {
+	tokens []Token
+	pos    int
+}
+
+func (p *parser) peek() Token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() Token {
+	token := p.tokens[p.pos]
+	if token.Kind != TokenEOF {
+		p.pos++
+	}
+	return token
+}
+
+func (p *parser) expect(kind TokenKind, description string) (Token, error) {
+	token := p.next()
+	if token.Kind != kind {
+		if token.Kind == TokenEOF {
+			return token, fmt.Errorf("expected %s but reached end of expression", description)
+		}
+		return token, fmt.Errorf("expected %s but found '%s' at position %d", description, token.Text, token.Pos)
+	}
+	return token, nil
+}
+
+func (p *parser) parseOr() (Node, error) {
+	return p.parseBinary(p.parseAnd, TokenOr)
+}
+
+func (p *parser) parseAnd() (Node, error) {
+	return p.parseBinary(p.parseEquality, TokenAnd)
+}
+
+func (p *parser) parseEquality() (Node, error) {
+	return p.parseBinary(p.parseComparison, TokenEqual, TokenNotEqual)
+}
+
+func (p *parser) parseComparison() (Node, error) {
+	return p.parseBinary(p.parseUnary, TokenLess, TokenLessEqual, TokenGreater, TokenGreaterEqual)
+}
+
+// parseBinary parses a left-associative chain of the given operators
+func (p *parser) parseBinary(operand func() (Node, error), operators ...TokenKind) (Node, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		kind := p.peek().Kind
+		matched := false
+		for _, operator := range operators {
+			if kind == operator {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return left, nil
+		}
+
+		p.next()
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		left = &BinaryNode{Operator: kind, Left: left, Right: right}
+	}
+}
+
+func (p *parser) parseUnary() (Node, error) {
+	if p.peek().Kind == TokenNot {
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &NotNode{Operand: operand}, nil
+	}
+	return p.parsePostfix()
+}
+
+// parsePostfix parses a primary expression followed by any .prop, .*, or [index] accessors
+func (p *parser) parsePostfix() (Node, error) {
+	node, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		switch p.peek().Kind {
+		case TokenDot:
+			p.next()
+			token := p.next()
+			switch token.Kind {
+			case TokenStar:
+				node = &FilterNode{Target: node}
+			case TokenIdentifier, TokenNull, TokenBool:
+				// Keywords are valid property names: github.event.inputs.true
+				node = &PropertyNode{Target: node, Property: token.Text}
+			default:
+				return nil, fmt.Errorf("expected property name after '.' at position %d", token.Pos)
+			}
+
+		case TokenLeftBracket:
+			p.next()
+			if p.peek().Kind == TokenStar {
+				p.next()
+				if _, err := p.expect(TokenRightBracket, "']'"); err != nil {
+					return nil, err
+				}
+				node = &FilterNode{Target: node}
+				continue
+			}
+
+			index, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if _, err := p.expect(TokenRightBracket, "']'"); err != nil {
+				return nil, err
+			}
+			node = &IndexNode{Target: node, Index: index}
+
+		default:
+			return node, nil
+		}
+	}
+}
+
+func (p *parser) parsePrimary() (Node, error) {
+	token := p.next()
+
+	switch token.Kind {
+	case TokenNull, TokenBool, TokenNumber, TokenString:
+		return &LiteralNode{Value: token.Value}, nil
+
+	case TokenLeftParen:
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(TokenRightParen, "')'"); err != nil {
+			return nil, err
+		}
+		return node, nil
+
+	case TokenIdentifier:
+		if p.peek().Kind == TokenLeftParen {
+			return p.parseFunctionCall(token)
+		}
+		return &ContextNode{Name: strings.ToLower(token.Text)}, nil
+
+	case TokenEOF:
+		return nil, fmt.Errorf("unexpected end of expression")
+
+	default:
+		return nil, fmt.Errorf("unexpected '%s' at position %d", token.Text, token.Pos)
+	}
+}
+
+func (p *parser) parseFunctionCall(name Token) (Node, error) {
+	p.next() // consume '('
+	call := &FunctionCallNode{Name: strings.ToLower(name.Text)}
+
+	if p.peek().Kind == TokenRightParen {
+		p.next()
+		return call, nil
+	}
+
+	for {
+		arg, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		call.Args = append(call.Args, arg)
+
+		token := p.next()
+		switch token.Kind {
+		case TokenComma:
+			continue
+		case TokenRightParen:
+			return call, nil
+		default:
+			return nil, fmt.Errorf("expected ',' or ')' in call to %s at position %d", name.Text, token.Pos)
+		}
+	}
+}
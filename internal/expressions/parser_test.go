@@ -0,0 +1,115 @@
+package expressions
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// describe prints a syntax tree with explicit grouping, e.g. ((a && b) || c)
+func describe(node Node) string {
+	switch n := node.(type) {
+	case *LiteralNode:
+		if s, ok := n.Value.(string); ok {
+			return "'" + s + "'"
+		}
+		return fmt.Sprintf("%v", n.Value)
+	case *ContextNode:
+		return n.Name
+	case *PropertyNode:
+		return describe(n.Target) + "." + n.Property
+	case *IndexNode:
+		return describe(n.Target) + "[" + describe(n.Index) + "]"
+	case *FilterNode:
+		return describe(n.Target) + ".*"
+	case *NotNode:
+		return "!" + describe(n.Operand)
+	case *BinaryNode:
+		operators := map[TokenKind]string{
+			TokenAnd: "&&", TokenOr: "||", TokenEqual: "==", TokenNotEqual: "!=",
+			TokenLess: "<", TokenLessEqual: "<=", TokenGreater: ">", TokenGreaterEqual: ">=",
+		}
+		return "(" + describe(n.Left) + " " + operators[n.Operator] + " " + describe(n.Right) + ")"
+	case *FunctionCallNode:
+		args := make([]string, len(n.Args))
+		for i, arg := range n.Args {
+			args[i] = describe(arg)
+		}
+		return n.Name + "(" + strings.Join(args, ", ") + ")"
+	default:
+		return fmt.Sprintf("%T", node)
+	}
+}
+
+func TestParsePrecedence(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       string
+	}{
+		{"a && b || c", "((a && b) || c)"},
+		{"a || b && c", "(a || (b && c))"},
+		{"a || b || c", "((a || b) || c)"},
+		{"a && (b || c)", "(a && (b || c))"},
+		{"a == b && c != d", "((a == b) && (c != d))"},
+		{"a < b == c > d", "((a < b) == (c > d))"},
+		{"!a && b", "(!a && b)"},
+		{"!a == b", "(!a == b)"},
+		{"!(a == b)", "!(a == b)"},
+		{"!!a", "!!a"},
+		{"a.b[0].c", "a.b[0].c"},
+		{"a.b.*.c", "a.b.*.c"},
+		{"a['x-y'].*[1]", "a['x-y'].*[1]"},
+		{"Contains(a.*, 'x') || b", "(contains(a.*, 'x') || b)"},
+		{"GitHub.Event_Name", "github.Event_Name"},
+		{"format('{0}', 1) == 'x'", "(format('{0}', 1) == 'x')"},
+		{"a.b-c", "a.b-c"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			node, err := Parse(test.expression)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", test.expression, err)
+			}
+			if got := describe(node); got != test.want {
+				t.Errorf("Parse(%q) = %s, want %s", test.expression, got, test.want)
+			}
+		})
+	}
+}
+
+func TestTokenizeNumbers(t *testing.T) {
+	tests := []struct {
+		text string
+		want float64
+	}{
+		{"0", 0},
+		{"10", 10},
+		{"-7", -7},
+		{"+7", 7},
+		{"1.25", 1.25},
+		{".5", 0.5},
+		{"0x1F", 31},
+		{"0xff", 255},
+		{"-0xff", -255},
+		{"0o755", 493},
+		{"1e3", 1000},
+		{"1E-2", 0.01},
+		{"6.02e+23", 6.02e+23},
+	}
+
+	for _, test := range tests {
+		tokens, err := tokenize(test.text)
+		if err != nil {
+			t.Errorf("tokenize(%q): %v", test.text, err)
+			continue
+		}
+		if len(tokens) != 2 || tokens[0].Kind != TokenNumber {
+			t.Errorf("tokenize(%q) = %+v, want a single number", test.text, tokens)
+			continue
+		}
+		if tokens[0].Value != test.want {
+			t.Errorf("tokenize(%q) = %v, want %v", test.text, tokens[0].Value, test.want)
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package expressions
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Expression values are nil, bool, float64, string, []interface{} or map[string]interface{}.
+// filteredArray marks the result of an object filter so further dereferences map over it.
+type filteredArray []interface{}
+
+// normalizeValue converts Go values from contexts and YAML into expression value types
+func normalizeValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil, bool, float64, string, []interface{}, map[string]interface{}, filteredArray:
+		return v
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	case float32:
+		return float64(v)
+	case map[string]string:
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			result[key] = item
+		}
+		return result
+	case []string:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			result[i] = item
+		}
+		return result
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			result[ToString(key)] = item
+		}
+		return result
+	default:
+		return ToString(v)
+	}
+}
+
+// IsTruthy applies GitHub's coercion to boolean: false, 0, -0, NaN, "" and null are falsy
+func IsTruthy(value interface{}) bool {
+	switch v := normalizeValue(value).(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case float64:
+		return v != 0 && !math.IsNaN(v)
+	case string:
+		return v != ""
+	default:
+		return true // Arrays and objects
+	}
+}
+
+// ToString converts a value to its string form as used in ${{ }} interpolation
+func ToString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case bool:
+		if v {
+			return "true"
+		}
+		return "false"
+	case float64:
+		return formatNumber(v)
+	case int, int64, uint64, float32:
+		return formatNumber(normalizeValue(v).(float64))
+	case string:
+		return v
+	case []interface{}, filteredArray, []string:
+		return "Array"
+	case map[string]interface{}, map[string]string:
+		return "Object"
+	default:
+		return reflect.ValueOf(v).String()
+	}
+}
+
+// toNumber applies GitHub's coercion to number; values that cannot convert become NaN
+func toNumber(value interface{}) float64 {
+	switch v := normalizeValue(value).(type) {
+	case nil:
+		return 0
+	case bool:
+		if v {
+			return 1
+		}
+		return 0
+	case float64:
+		return v
+	case string:
+		trimmed := strings.TrimSpace(v)
+		if trimmed == "" {
+			return 0
+		}
+		number, err := parseNumber(trimmed)
+		if err != nil {
+			return math.NaN()
+		}
+		return number
+	default:
+		return math.NaN()
+	}
+}
+
+func formatNumber(number float64) string {
+	switch {
+	case math.IsNaN(number):
+		return "NaN"
+	case math.IsInf(number, 1):
+		return "Infinity"
+	case math.IsInf(number, -1):
+		return "-Infinity"
+	default:
+		return strconv.FormatFloat(number, 'f', -1, 64)
+	}
+}
+
+// valueKind groups values for comparison purposes
+func valueKind(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}, filteredArray:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// looseEqual implements GitHub's == operator: mismatched types are coerced to numbers,
+// strings compare case-insensitively and arrays/objects only equal themselves
+func looseEqual(left, right interface{}) bool {
+	left = normalizeValue(left)
+	right = normalizeValue(right)
+
+	if valueKind(left) != valueKind(right) {
+		l, r := toNumber(left), toNumber(right)
+		return !math.IsNaN(l) && !math.IsNaN(r) && l == r
+	}
+
+	switch l := left.(type) {
+	case nil:
+		return true
+	case bool:
+		return l == right.(bool)
+	case float64:
+		return l == right.(float64)
+	case string:
+		return strings.EqualFold(l, right.(string))
+	default:
+		return sameInstance(left, right)
+	}
+}
+
+// compareValues implements <, <=, > and >= with the same coercion as looseEqual
+func compareValues(operator TokenKind, left, right interface{}) bool {
+	left = normalizeValue(left)
+	right = normalizeValue(right)
+
+	var cmp int
+	ls, leftIsString := left.(string)
+	rs, rightIsString := right.(string)
+
+	if leftIsString && rightIsString {
+		cmp = strings.Compare(strings.ToUpper(ls), strings.ToUpper(rs))
+	} else {
+		l, r := toNumber(left), toNumber(right)
+		if math.IsNaN(l) || math.IsNaN(r) {
+			return false
+		}
+		switch {
+		case l < r:
+			cmp = -1
+		case l > r:
+			cmp = 1
+		}
+	}
+
+	switch operator {
+	case TokenLess:
+		return cmp < 0
+	case TokenLessEqual:
+		return cmp <= 0
+	case TokenGreater:
+		return cmp > 0
+	case TokenGreaterEqual:
+		return cmp >= 0
+	default:
+		return false
+	}
+}
+
+func sameInstance(left, right interface{}) bool {
+	lv, rv := reflect.ValueOf(left), reflect.ValueOf(right)
+	if lv.Kind() != rv.Kind() {
+		return false
+	}
+	switch lv.Kind() {
+	case reflect.Map, reflect.Slice:
+		return lv.Pointer() == rv.Pointer() && lv.Len() == rv.Len()
+	default:
+		return false
+	}
+}
+
+// lookupProperty finds an object property, matching names case-insensitively like GitHub
+func lookupProperty(object map[string]interface{}, name string) (interface{}, bool) {
+	if value, exists := object[name]; exists {
+		return value, true
+	}
+	for key, value := range object {
+		if strings.EqualFold(key, name) {
+			return value, true
+		}
+	}
+	return nil, false
+}
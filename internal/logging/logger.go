@@ -5,8 +5,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
 	"time"
+
+	"github.com/Neoxs/gogh/internal/secrets"
 )
 
 // WorkflowLogger manages logging for an entire workflow execution
@@ -14,14 +18,18 @@ type WorkflowLogger struct {
 	workflowFile *os.File
 	jobLoggers   map[string]*JobLogger
 	basePath     string
+	masker       *secrets.Masker // Hides secret values in every log line
 	mu           sync.RWMutex
+	fileMu       sync.Mutex // Guards writes to workflowFile from concurrent jobs
 }
 
 // JobLogger handles logging for a specific job
 type JobLogger struct {
-	jobFile *os.File
-	jobID   string
-	mu      sync.Mutex
+	jobFile     *os.File
+	jobID       string
+	summaryPath string // Markdown written to GITHUB_STEP_SUMMARY by the job's steps
+	masker      *secrets.Masker
+	mu          sync.Mutex
 }
 
 // LogLevel represents different types of log entries
@@ -38,7 +46,22 @@ const (
 func NewWorkflowLogger(workflowName, projectDir string) (*WorkflowLogger, error) {
 	timestamp := time.Now().Format("2006-01-02-15-04-05")
 	basePath := filepath.Join(projectDir, "gogh-logs", fmt.Sprintf("workflow-%s", timestamp))
+	return NewWorkflowLoggerAt(workflowName, basePath)
+}
 
+// NewRunLogRoot creates the directory holding the logs of several workflows run together;
+// each workflow logs to its own subdirectory through NewWorkflowLoggerAt
+func NewRunLogRoot(projectDir string) (string, error) {
+	timestamp := time.Now().Format("2006-01-02-15-04-05")
+	root := filepath.Join(projectDir, "gogh-logs", fmt.Sprintf("run-%s", timestamp))
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return "", fmt.Errorf("failed to create log directory: %w", err)
+	}
+	return root, nil
+}
+
+// NewWorkflowLoggerAt creates a workflow logger writing to the given directory
+func NewWorkflowLoggerAt(workflowName, basePath string) (*WorkflowLogger, error) {
 	// Create logs directory
 	if err := os.MkdirAll(basePath, 0755); err != nil {
 		return nil, fmt.Errorf("failed to create log directory: %w", err)
@@ -60,6 +83,19 @@ func NewWorkflowLogger(workflowName, projectDir string) (*WorkflowLogger, error)
 	return logger, nil
 }
 
+// SetMasker masks secret values in the workflow log and every job log
+func (wl *WorkflowLogger) SetMasker(masker *secrets.Masker) {
+	wl.mu.Lock()
+	defer wl.mu.Unlock()
+
+	wl.masker = masker
+	for _, jobLogger := range wl.jobLoggers {
+		jobLogger.mu.Lock()
+		jobLogger.masker = masker
+		jobLogger.mu.Unlock()
+	}
+}
+
 // GetJobLogger returns or creates a logger for a specific job
 func (wl *WorkflowLogger) GetJobLogger(jobID string) (*JobLogger, error) {
 	wl.mu.RLock()
@@ -78,7 +114,7 @@ func (wl *WorkflowLogger) GetJobLogger(jobID string) (*JobLogger, error) {
 	}
 
 	timestamp := time.Now().Format("2006-01-02-15-04-05")
-	jobLogFile := filepath.Join(wl.basePath, fmt.Sprintf("%s-%s.log", jobID, timestamp))
+	jobLogFile := filepath.Join(wl.basePath, fmt.Sprintf("%s-%s.log", sanitizeFileName(jobID), timestamp))
 
 	jobFile, err := os.Create(jobLogFile)
 	if err != nil {
@@ -86,8 +122,10 @@ func (wl *WorkflowLogger) GetJobLogger(jobID string) (*JobLogger, error) {
 	}
 
 	jobLogger := &JobLogger{
-		jobFile: jobFile,
-		jobID:   jobID,
+		jobFile:     jobFile,
+		jobID:       jobID,
+		summaryPath: filepath.Join(wl.basePath, fmt.Sprintf("%s-summary.md", sanitizeFileName(jobID))),
+		masker:      wl.masker,
 	}
 
 	wl.jobLoggers[jobID] = jobLogger
@@ -101,6 +139,14 @@ func (wl *WorkflowLogger) LogWorkflowStart(workflowName string) {
 	wl.writeWorkflowLog("##[endgroup]")
 }
 
+// LogEvent logs the simulated event and where its payload came from
+func (wl *WorkflowLogger) LogEvent(eventName, payloadPath string) {
+	if payloadPath == "" {
+		payloadPath = "synthesized from git state"
+	}
+	wl.writeWorkflowLog(fmt.Sprintf("Event: %s (payload: %s)", eventName, payloadPath))
+}
+
 // LogWorkflowComplete logs successful workflow completion
 func (wl *WorkflowLogger) LogWorkflowComplete(duration time.Duration) {
 	wl.writeWorkflowLog("##[group]Workflow completed successfully")
@@ -121,6 +167,42 @@ func (wl *WorkflowLogger) LogExecutionPlan(executionOrder []string) {
 	wl.writeWorkflowLog("##[endgroup]")
 }
 
+// LogWorkflowCall logs a job calling a reusable workflow, with the inputs it passes
+func (wl *WorkflowLogger) LogWorkflowCall(jobName, workflowFile string, inputs map[string]interface{}, executionOrder []string) {
+	names := make([]string, 0, len(inputs))
+	for name := range inputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	wl.writeWorkflowLog(fmt.Sprintf("##[group]Job '%s' calls %s", jobName, workflowFile))
+	for _, name := range names {
+		wl.writeWorkflowLog(fmt.Sprintf("Input %s=%v", name, inputs[name]))
+	}
+	wl.writeWorkflowLog(fmt.Sprintf("Job execution order: %v", executionOrder))
+	wl.writeWorkflowLog("##[endgroup]")
+}
+
+// LogJobSkipped logs a job that was not run because a dependency did not succeed
+func (wl *WorkflowLogger) LogJobSkipped(jobID, reason string) {
+	wl.writeWorkflowLog(fmt.Sprintf("Job '%s' skipped: %s", jobID, reason))
+}
+
+// LogJobOutputs logs the outputs a job hands to the jobs that need it
+func (wl *WorkflowLogger) LogJobOutputs(jobID string, outputs map[string]string) {
+	keys := make([]string, 0, len(outputs))
+	for key := range outputs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	wl.writeWorkflowLog(fmt.Sprintf("##[group]Outputs of job '%s'", jobID))
+	for _, key := range keys {
+		wl.writeWorkflowLog(fmt.Sprintf("%s=%s", key, outputs[key]))
+	}
+	wl.writeWorkflowLog("##[endgroup]")
+}
+
 // JobLogger methods
 
 // LogJobStart logs the beginning of a job
@@ -153,6 +235,21 @@ func (jl *JobLogger) LogStepOutput(line string) {
 	jl.writeJobLog(line)
 }
 
+// LogStepError logs why a step failed
+func (jl *JobLogger) LogStepError(err error) {
+	jl.writeJobLog(fmt.Sprintf("##[error]%v", err))
+}
+
+// LogWarning logs a warning that does not fail the step
+func (jl *JobLogger) LogWarning(message string) {
+	jl.writeJobLog(fmt.Sprintf("##[warning]%s", message))
+}
+
+// LogStepSkipped logs a step whose if: condition evaluated to false
+func (jl *JobLogger) LogStepSkipped(stepName, condition string) {
+	jl.writeJobLog(fmt.Sprintf("##[section]Step '%s' skipped (condition: %s)", stepName, condition))
+}
+
 // LogStepComplete logs step completion with timing
 func (jl *JobLogger) LogStepComplete(stepName string, duration time.Duration, exitCode int) {
 	if exitCode == 0 {
@@ -162,6 +259,24 @@ func (jl *JobLogger) LogStepComplete(stepName string, duration time.Duration, ex
 	}
 }
 
+// AppendStepSummary adds a step's GITHUB_STEP_SUMMARY markdown to the job summary file
+func (jl *JobLogger) AppendStepSummary(stepName, markdown string) error {
+	jl.mu.Lock()
+	summaryFile, err := os.OpenFile(jl.summaryPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err == nil {
+		_, err = summaryFile.WriteString(jl.masker.Mask(strings.TrimRight(markdown, "\n")) + "\n\n")
+		summaryFile.Close()
+	}
+	jl.mu.Unlock()
+
+	if err != nil {
+		return fmt.Errorf("failed to write job summary: %w", err)
+	}
+
+	jl.writeJobLog(fmt.Sprintf("Step summary for '%s' written to %s", stepName, jl.summaryPath))
+	return nil
+}
+
 // LogJobComplete logs job completion
 func (jl *JobLogger) LogJobComplete(jobID string, duration time.Duration) {
 	jl.writeJobLog("##[group]Job Summary")
@@ -198,12 +313,15 @@ Started:  %s
 ==============================================
 `, workflowName, time.Now().Format("2006-01-02 15:04:05 MST"))
 
-	wl.workflowFile.WriteString(header)
+	wl.workflowFile.WriteString(wl.masker.Mask(header))
 }
 
 func (wl *WorkflowLogger) writeWorkflowLog(message string) {
 	timestamp := time.Now().UTC().Format("2006-01-02T15:04:05.0000000Z")
-	line := fmt.Sprintf("%s %s\n", timestamp, message)
+	line := fmt.Sprintf("%s %s\n", timestamp, wl.masker.Mask(message))
+
+	wl.fileMu.Lock()
+	defer wl.fileMu.Unlock()
 
 	if wl.workflowFile != nil {
 		wl.workflowFile.WriteString(line)
@@ -216,7 +334,7 @@ func (jl *JobLogger) writeJobLog(message string) {
 	defer jl.mu.Unlock()
 
 	timestamp := time.Now().UTC().Format("2006-01-02T15:04:05.0000000Z")
-	line := fmt.Sprintf("%s %s\n", timestamp, message)
+	line := fmt.Sprintf("%s %s\n", timestamp, jl.masker.Mask(message))
 
 	if jl.jobFile != nil {
 		jl.jobFile.WriteString(line)
@@ -258,6 +376,20 @@ func (jl *JobLogger) Close() error {
 	return nil
 }
 
+// sanitizeFileName turns a job name such as "test (18, ubuntu)" into a safe file name
+func sanitizeFileName(name string) string {
+	var builder strings.Builder
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			builder.WriteRune(r)
+		default:
+			builder.WriteRune('_')
+		}
+	}
+	return builder.String()
+}
+
 // GetLogPath returns the base path where logs are stored
 func (wl *WorkflowLogger) GetLogPath() string {
 	return wl.basePath
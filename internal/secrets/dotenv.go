@@ -0,0 +1,113 @@
+package secrets
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// LoadFile reads secrets from a dotenv file, e.g. one passed with --secret-file
+func LoadFile(path string) (map[string]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secret file: %w", err)
+	}
+
+	values, err := ParseDotenv(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return values, nil
+}
+
+// ParseDotenv parses KEY=VALUE lines in dotenv format. Blank lines and # comments are
+// ignored and an optional "export " prefix is dropped. Double-quoted values may span
+// lines and understand \n, \t, \" and \\ escapes; single-quoted values are taken literally.
+func ParseDotenv(content string) (map[string]string, error) {
+	values := make(map[string]string)
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+
+	for i := 0; i < len(lines); i++ {
+		lineNumber := i + 1
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, value, found := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE", lineNumber)
+		}
+		value = strings.TrimSpace(value)
+
+		switch {
+		case strings.HasPrefix(value, `"`):
+			// Keep reading lines until the closing quote
+			quoted := value[1:]
+			for !hasClosingQuote(quoted) {
+				i++
+				if i >= len(lines) {
+					return nil, fmt.Errorf("line %d: unterminated quoted value for %s", lineNumber, key)
+				}
+				quoted += "\n" + lines[i]
+			}
+			value = unescapeDoubleQuoted(quoted[:closingQuote(quoted)])
+		case strings.HasPrefix(value, "'"):
+			end := strings.Index(value[1:], "'")
+			if end == -1 {
+				return nil, fmt.Errorf("line %d: unterminated quoted value for %s", lineNumber, key)
+			}
+			value = value[1 : end+1]
+		default:
+			// Unquoted values may carry a trailing comment
+			if comment := strings.Index(value, " #"); comment != -1 {
+				value = strings.TrimSpace(value[:comment])
+			}
+		}
+
+		values[key] = value
+	}
+
+	return values, nil
+}
+
+// closingQuote returns the index of the first unescaped double quote, or -1
+func closingQuote(value string) int {
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+func hasClosingQuote(value string) bool {
+	return closingQuote(value) != -1
+}
+
+func unescapeDoubleQuoted(value string) string {
+	var builder strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '\\' || i+1 == len(value) {
+			builder.WriteByte(value[i])
+			continue
+		}
+		i++
+		switch value[i] {
+		case 'n':
+			builder.WriteByte('\n')
+		case 't':
+			builder.WriteByte('\t')
+		case 'r':
+			builder.WriteByte('\r')
+		default:
+			builder.WriteByte(value[i]) // \" and \\ and anything else
+		}
+	}
+	return builder.String()
+}
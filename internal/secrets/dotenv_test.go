@@ -0,0 +1,100 @@
+package secrets
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseDotenv(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    map[string]string
+	}{
+		{"empty", "", map[string]string{}},
+		{"plain values", "TOKEN=abc\nPASSWORD=hunter2\n", map[string]string{"TOKEN": "abc", "PASSWORD": "hunter2"}},
+		{"comments and blank lines", "# secrets\n\nTOKEN=abc\n  # indented comment\n", map[string]string{"TOKEN": "abc"}},
+		{"spaces around key and value", "  TOKEN =  abc  \n", map[string]string{"TOKEN": "abc"}},
+		{"value keeps =", "URL=https://host/?a=1&b=2", map[string]string{"URL": "https://host/?a=1&b=2"}},
+		{"empty value", "EMPTY=\n", map[string]string{"EMPTY": ""}},
+		{"trailing comment", "TOKEN=abc # the api token", map[string]string{"TOKEN": "abc"}},
+		{"hash without a space is kept", "COLOR=#ff0000\nTAG=a#b", map[string]string{"COLOR": "#ff0000", "TAG": "a#b"}},
+		{"export prefix", "export TOKEN=abc\nexport  SPACED=1", map[string]string{"TOKEN": "abc", "SPACED": "1"}},
+		{"export as a key", "export=1", map[string]string{"export": "1"}},
+		{"later values win", "TOKEN=old\nTOKEN=new", map[string]string{"TOKEN": "new"}},
+		{"crlf line endings", "A=1\r\nB=\"x\r\ny\"\r\n", map[string]string{"A": "1", "B": "x\ny"}},
+
+		{"double quotes", `TOKEN="abc def"`, map[string]string{"TOKEN": "abc def"}},
+		{"double quotes keep #", `TOKEN="abc # not a comment" # a comment`, map[string]string{"TOKEN": "abc # not a comment"}},
+		{"double quote escapes", `TOKEN="a\nb\tc\"d\\e\$f"`, map[string]string{"TOKEN": "a\nb\tc\"d\\e$f"}},
+		{"single quotes are literal", `TOKEN='a\nb "c" $d'`, map[string]string{"TOKEN": `a\nb "c" $d`}},
+		{"empty quotes", `A=""` + "\n" + `B=''`, map[string]string{"A": "", "B": ""}},
+		{
+			"multiline double quotes",
+			"KEY=\"-----BEGIN KEY-----\nline one\n  indented\n-----END KEY-----\"\nNEXT=1",
+			map[string]string{"KEY": "-----BEGIN KEY-----\nline one\n  indented\n-----END KEY-----", "NEXT": "1"},
+		},
+		{
+			"multiline with an escaped quote on a continued line",
+			"JSON=\"{\n  \\\"a\\\": 1\n}\"",
+			map[string]string{"JSON": "{\n  \"a\": 1\n}"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDotenv(tt.content)
+			if err != nil {
+				t.Fatalf("ParseDotenv(%q) returned error: %v", tt.content, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseDotenv(%q) = %q, want %q", tt.content, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDotenvErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		line    string
+	}{
+		{"no =", "TOKEN=abc\nPASSWORD\n", "line 2"},
+		{"empty key", "=abc", "line 1"},
+		{"unterminated double quotes", "A=1\nTOKEN=\"abc\nmore", "line 2"},
+		{"escaped closing quote", `TOKEN="abc\"`, "line 1"},
+		{"unterminated single quotes", "TOKEN='abc", "line 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDotenv(tt.content)
+			if err == nil {
+				t.Fatalf("ParseDotenv(%q) = %q, want an error", tt.content, got)
+			}
+			if !strings.Contains(err.Error(), tt.line) {
+				t.Errorf("ParseDotenv(%q) error %q does not mention %s", tt.content, err, tt.line)
+			}
+		})
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".secrets")
+	if err := os.WriteFile(path, []byte("TOKEN=abc\nBROKEN\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := LoadFile(path)
+	if err == nil || !strings.Contains(err.Error(), path) {
+		t.Errorf("LoadFile() error = %v, want one naming %s", err, path)
+	}
+
+	if _, err := LoadFile(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Errorf("LoadFile() of a missing file succeeded")
+	}
+}
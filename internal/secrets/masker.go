@@ -0,0 +1,71 @@
+package secrets
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Placeholder replaces secret values wherever they would be shown
+const Placeholder = "***"
+
+// Masker replaces registered secret values with *** in text bound for logs and the terminal
+type Masker struct {
+	values []string // Longest first, so a secret containing another is masked whole
+	mu     sync.RWMutex
+}
+
+// NewMasker creates a masker for the given secret values
+func NewMasker(values ...string) *Masker {
+	masker := &Masker{}
+	for _, value := range values {
+		masker.Add(value)
+	}
+	return masker
+}
+
+// Add registers a secret value. Each line of a multi-line value is also masked on its own,
+// since output is logged line by line.
+func (m *Masker) Add(value string) {
+	candidates := []string{value}
+	if strings.Contains(value, "\n") {
+		candidates = append(candidates, strings.Split(strings.ReplaceAll(value, "\r\n", "\n"), "\n")...)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, candidate := range candidates {
+		if strings.TrimSpace(candidate) == "" || m.contains(candidate) {
+			continue
+		}
+		m.values = append(m.values, candidate)
+	}
+	sort.SliceStable(m.values, func(i, j int) bool {
+		return len(m.values[i]) > len(m.values[j])
+	})
+}
+
+// Mask returns text with every registered secret value replaced by ***
+func (m *Masker) Mask(text string) string {
+	if m == nil {
+		return text
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, value := range m.values {
+		text = strings.ReplaceAll(text, value, Placeholder)
+	}
+	return text
+}
+
+func (m *Masker) contains(value string) bool {
+	for _, existing := range m.values {
+		if existing == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+package secrets
+
+import "testing"
+
+func TestMasker(t *testing.T) {
+	tests := []struct {
+		name    string
+		secrets []string
+		text    string
+		want    string
+	}{
+		{"no secrets", nil, "token=abc", "token=abc"},
+		{"single secret", []string{"abc"}, "token=abc", "token=***"},
+		{"every occurrence", []string{"abc"}, "abc and abc", "*** and ***"},
+		{"longest first", []string{"abc", "abcdef"}, "key=abcdef", "key=***"},
+		{"longest first whatever the order", []string{"abcdef", "abc"}, "key=abcdef, short=abc", "key=***, short=***"},
+		{"overlapping secrets", []string{"pass", "password123"}, "password123 pass", "*** ***"},
+		{"blank secrets are ignored", []string{"", "  ", "\n"}, "a b\nc", "a b\nc"},
+		{"duplicates", []string{"abc", "abc"}, "abc", "***"},
+		{
+			"multiline secret masked whole",
+			[]string{"line one\nline two"},
+			"key:\nline one\nline two\n",
+			"key:\n***\n",
+		},
+		{
+			"each line of a multiline secret masked alone",
+			[]string{"-----BEGIN-----\nc2VjcmV0\n-----END-----"},
+			"got c2VjcmV0 from -----END-----",
+			"got *** from ***",
+		},
+		{"crlf multiline secret", []string{"first\r\nsecond"}, "second", "***"},
+		{"blank lines of a multiline secret are kept", []string{"top\n\nbottom"}, "a\n\nb", "a\n\nb"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			masker := NewMasker(tt.secrets...)
+			if got := masker.Mask(tt.text); got != tt.want {
+				t.Errorf("Mask(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaskerAddAfterCreation(t *testing.T) {
+	masker := NewMasker("short")
+	masker.Add("shorter-but-longer")
+
+	if got, want := masker.Mask("shorter-but-longer short"), "*** ***"; got != want {
+		t.Errorf("Mask() = %q, want %q", got, want)
+	}
+}
+
+func TestNilMasker(t *testing.T) {
+	var masker *Masker
+	if got := masker.Mask("abc"); got != "abc" {
+		t.Errorf("Mask() on a nil masker = %q, want the text unchanged", got)
+	}
+}
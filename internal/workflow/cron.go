@@ -0,0 +1,161 @@
+package workflow
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cron is a parsed POSIX cron expression as used by on.schedule; schedules run in UTC
+type Cron struct {
+	minutes, hours, days, months, weekdays map[int]bool
+
+	restrictedDays, restrictedWeekdays bool // A day matches either field when both are restricted
+}
+
+// cronField describes the allowed range and names of one cron field
+type cronField struct {
+	name     string
+	min, max int
+	names    []string // Names for values starting at min, e.g. JAN or SUN
+}
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: []string{"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}},
+	{name: "day of week", min: 0, max: 6, names: []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}},
+}
+
+// ParseCron parses the five fields minute, hour, day of month, month and day of week.
+// Each field accepts *, values, names, ranges (1-5), lists (1,3) and steps (*/15, 1-30/5).
+func ParseCron(expression string) (*Cron, error) {
+	fields := strings.Fields(expression)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("invalid cron '%s': expected 5 fields, got %d", expression, len(fields))
+	}
+
+	sets := make([]map[int]bool, len(fields))
+	for i, field := range fields {
+		set, err := cronFields[i].parse(field)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron '%s': %w", expression, err)
+		}
+		sets[i] = set
+	}
+
+	// Day of week 7 is another name for Sunday
+	if sets[4][7] {
+		sets[4][0] = true
+	}
+
+	return &Cron{
+		minutes:            sets[0],
+		hours:              sets[1],
+		days:               sets[2],
+		months:             sets[3],
+		weekdays:           sets[4],
+		restrictedDays:     fields[2] != "*",
+		restrictedWeekdays: fields[4] != "*",
+	}, nil
+}
+
+// Next returns the first time after the given one at which the schedule fires
+func (c *Cron) Next(after time.Time) time.Time {
+	next := after.Truncate(time.Minute).Add(time.Minute)
+	limit := next.AddDate(5, 0, 0) // Dates such as Feb 30 never match
+
+	for next.Before(limit) {
+		switch {
+		case !c.months[int(next.Month())]:
+			next = time.Date(next.Year(), next.Month()+1, 1, 0, 0, 0, 0, next.Location())
+		case !c.matchesDay(next):
+			next = time.Date(next.Year(), next.Month(), next.Day()+1, 0, 0, 0, 0, next.Location())
+		case !c.hours[next.Hour()]:
+			next = next.Truncate(time.Hour).Add(time.Hour)
+		case !c.minutes[next.Minute()]:
+			next = next.Add(time.Minute)
+		default:
+			return next
+		}
+	}
+	return time.Time{}
+}
+
+// matchesDay follows cron's rule that when both day fields are restricted either may match
+func (c *Cron) matchesDay(t time.Time) bool {
+	dayMatches := c.days[t.Day()]
+	weekdayMatches := c.weekdays[int(t.Weekday())]
+	if c.restrictedDays && c.restrictedWeekdays {
+		return dayMatches || weekdayMatches
+	}
+	return dayMatches && weekdayMatches
+}
+
+// parse expands one cron field into the set of values it allows
+func (f cronField) parse(field string) (map[int]bool, error) {
+	max := f.max
+	if f.name == "day of week" {
+		max = 7
+	}
+
+	set := make(map[int]bool)
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepPart); err != nil || step <= 0 {
+				return nil, fmt.Errorf("invalid step '%s' in %s field", stepPart, f.name)
+			}
+		}
+
+		var low, high int
+		switch {
+		case rangePart == "*":
+			low, high = f.min, f.max
+		case strings.Contains(rangePart, "-"):
+			lowPart, highPart, _ := strings.Cut(rangePart, "-")
+			var err error
+			if low, err = f.value(lowPart); err != nil {
+				return nil, err
+			}
+			if high, err = f.value(highPart); err != nil {
+				return nil, err
+			}
+		default:
+			value, err := f.value(rangePart)
+			if err != nil {
+				return nil, err
+			}
+			low, high = value, value
+			if hasStep {
+				high = f.max // 5/15 means every 15 starting at 5
+			}
+		}
+
+		if low < f.min || high > max || low > high {
+			return nil, fmt.Errorf("%s field '%s' is out of range %d-%d", f.name, part, f.min, f.max)
+		}
+		for value := low; value <= high; value += step {
+			set[value] = true
+		}
+	}
+	return set, nil
+}
+
+// value parses a number or a name such as MON
+func (f cronField) value(text string) (int, error) {
+	for i, name := range f.names {
+		if strings.EqualFold(text, name) {
+			return f.min + i, nil
+		}
+	}
+	value, err := strconv.Atoi(text)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value '%s' in %s field", text, f.name)
+	}
+	return value, nil
+}
@@ -0,0 +1,80 @@
+package workflow
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCronNext(t *testing.T) {
+	// A Friday
+	after := time.Date(2026, time.October, 16, 10, 7, 30, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		cron string
+		want time.Time
+	}{
+		{"every minute", "* * * * *", time.Date(2026, time.October, 16, 10, 8, 0, 0, time.UTC)},
+		{"step", "*/15 * * * *", time.Date(2026, time.October, 16, 10, 15, 0, 0, time.UTC)},
+		{"step from a value", "5/15 * * * *", time.Date(2026, time.October, 16, 10, 20, 0, 0, time.UTC)},
+		{"list", "0,30 * * * *", time.Date(2026, time.October, 16, 10, 30, 0, 0, time.UTC)},
+		{"range with step", "0 8-18/4 * * *", time.Date(2026, time.October, 16, 12, 0, 0, 0, time.UTC)},
+		{"daily", "0 0 * * *", time.Date(2026, time.October, 17, 0, 0, 0, 0, time.UTC)},
+		{"later today", "30 14 * * *", time.Date(2026, time.October, 16, 14, 30, 0, 0, time.UTC)},
+		{"day of month", "0 12 13 * *", time.Date(2026, time.November, 13, 12, 0, 0, 0, time.UTC)},
+		{"day of week", "0 12 * * MON", time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)},
+		{"sunday as 7", "0 12 * * 7", time.Date(2026, time.October, 18, 12, 0, 0, 0, time.UTC)},
+		{"weekday range", "0 9 * * MON-FRI", time.Date(2026, time.October, 19, 9, 0, 0, 0, time.UTC)},
+		{"month names", "0 0 1 JAN *", time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC)},
+
+		// When both day fields are restricted a day matching either one fires
+		{"day of month or day of week", "0 12 13 * MON", time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)},
+		{"day of month before day of week", "0 12 17 * MON", time.Date(2026, time.October, 17, 12, 0, 0, 0, time.UTC)},
+		{"friday or the 13th", "0 12 13 * 5", time.Date(2026, time.October, 16, 12, 0, 0, 0, time.UTC)},
+		// Otherwise a * field matches every day and the other decides
+		{"weekdays in january", "0 9 * JAN MON-FRI", time.Date(2027, time.January, 1, 9, 0, 0, 0, time.UTC)},
+
+		{"leap day", "0 0 29 2 *", time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"impossible date", "0 0 30 2 *", time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cron, err := ParseCron(tt.cron)
+			if err != nil {
+				t.Fatalf("ParseCron(%q) returned error: %v", tt.cron, err)
+			}
+			if got := cron.Next(after); !got.Equal(tt.want) {
+				t.Errorf("ParseCron(%q).Next(%v) = %v, want %v", tt.cron, after, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCronErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		cron string
+	}{
+		{"too few fields", "* * * *"},
+		{"too many fields", "* * * * * *"},
+		{"minute out of range", "60 * * * *"},
+		{"hour out of range", "0 24 * * *"},
+		{"day of month zero", "0 0 0 * *"},
+		{"month out of range", "0 0 1 13 *"},
+		{"day of week out of range", "0 0 * * 8"},
+		{"reversed range", "0 0 * * FRI-MON"},
+		{"zero step", "*/0 * * * *"},
+		{"invalid step", "*/x * * * *"},
+		{"unknown name", "0 0 * * FUN"},
+		{"not a number", "a * * * *"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseCron(tt.cron); err == nil {
+				t.Errorf("ParseCron(%q) succeeded, want an error", tt.cron)
+			}
+		})
+	}
+}
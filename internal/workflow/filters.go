@@ -0,0 +1,256 @@
+package workflow
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// defaultPullRequestTypes are the activity types pull_request runs for when no types are listed
+var defaultPullRequestTypes = []string{"opened", "synchronize", "reopened"}
+
+// TriggerEvent describes an event to test against a workflow's triggers
+type TriggerEvent struct {
+	Name         string   // Event name, e.g. push or pull_request
+	Ref          string   // Full ref, e.g. refs/heads/main or refs/tags/v1.0
+	BaseRef      string   // Target branch of a pull request
+	Action       string   // Activity type, e.g. opened or published
+	ChangedFiles []string // Files changed by the event; nil when unknown
+}
+
+// TriggerResult explains whether a workflow fires for an event
+type TriggerResult struct {
+	Fires   bool
+	Reasons []string // Why the workflow fires, or the first filter that stopped it
+}
+
+// Evaluate applies GitHub's trigger rules: the event must be listed, its activity type
+// must be allowed, and its branch, tag and path filters must match
+func (t *Triggers) Evaluate(event TriggerEvent) TriggerResult {
+	trigger, listed := t.Events[event.Name]
+	if !listed {
+		return rejected("not triggered by %s (listens for %s)", event.Name, strings.Join(t.Order, ", "))
+	}
+
+	result := TriggerResult{Fires: true, Reasons: []string{fmt.Sprintf("listens for %s", event.Name)}}
+
+	if event.Name == "schedule" {
+		for _, schedule := range t.Schedule {
+			reason := fmt.Sprintf("cron '%s'", schedule.Cron)
+			if cron, err := ParseCron(schedule.Cron); err == nil {
+				reason += fmt.Sprintf(", next run %s", cron.Next(time.Now().UTC()).Format("2006-01-02 15:04 MST"))
+			}
+			result.Reasons = append(result.Reasons, reason)
+		}
+		return result
+	}
+
+	if reason, ok := trigger.matchTypes(event); !ok {
+		return rejected("%s", reason)
+	} else if reason != "" {
+		result.Reasons = append(result.Reasons, reason)
+	}
+
+	if reason, ok := trigger.matchRefs(event); !ok {
+		return rejected("%s", reason)
+	} else if reason != "" {
+		result.Reasons = append(result.Reasons, reason)
+	}
+
+	if reason, ok := trigger.matchPaths(event); !ok {
+		return rejected("%s", reason)
+	} else if reason != "" {
+		result.Reasons = append(result.Reasons, reason)
+	}
+
+	return result
+}
+
+// matchTypes checks the event's activity type against types:
+func (e *EventTrigger) matchTypes(event TriggerEvent) (string, bool) {
+	types := []string(e.Types)
+	if len(types) == 0 && (event.Name == "pull_request" || event.Name == "pull_request_target") {
+		types = defaultPullRequestTypes
+	}
+	if len(types) == 0 {
+		return "", true
+	}
+
+	if event.Action == "" {
+		return fmt.Sprintf("types [%s] need an activity type (pass --type)", strings.Join(types, ", ")), false
+	}
+	for _, activity := range types {
+		if activity == event.Action {
+			return fmt.Sprintf("activity type '%s' is in types [%s]", event.Action, strings.Join(types, ", ")), true
+		}
+	}
+	return fmt.Sprintf("activity type '%s' is not in types [%s]", event.Action, strings.Join(types, ", ")), false
+}
+
+// matchRefs applies branches and tags filters. Pushes match the pushed ref, so configuring
+// only branch filters ignores tag pushes and vice versa; pull requests match their base branch.
+func (e *EventTrigger) matchRefs(event TriggerEvent) (string, bool) {
+	switch event.Name {
+	case "push":
+		hasBranchFilters := len(e.Branches) > 0 || len(e.BranchesIgnore) > 0
+		hasTagFilters := len(e.Tags) > 0 || len(e.TagsIgnore) > 0
+
+		if tag, isTag := strings.CutPrefix(event.Ref, "refs/tags/"); isTag {
+			if !hasTagFilters && hasBranchFilters {
+				return fmt.Sprintf("tag '%s' is ignored because only branch filters are configured", tag), false
+			}
+			return matchRefFilters("tag", tag, e.Tags, e.TagsIgnore)
+		}
+
+		branch := strings.TrimPrefix(event.Ref, "refs/heads/")
+		if !hasBranchFilters && hasTagFilters {
+			return fmt.Sprintf("branch '%s' is ignored because only tag filters are configured", branch), false
+		}
+		return matchRefFilters("branch", branch, e.Branches, e.BranchesIgnore)
+
+	case "pull_request", "pull_request_target":
+		return matchRefFilters("base branch", event.BaseRef, e.Branches, e.BranchesIgnore)
+
+	default:
+		return "", true
+	}
+}
+
+// matchPaths applies paths filters: the workflow runs when at least one changed file
+// is included, or for paths-ignore when at least one changed file is not ignored
+func (e *EventTrigger) matchPaths(event TriggerEvent) (string, bool) {
+	if len(e.Paths) == 0 && len(e.PathsIgnore) == 0 {
+		return "", true
+	}
+	if event.Name != "push" && event.Name != "pull_request" && event.Name != "pull_request_target" {
+		return "", true
+	}
+	if strings.HasPrefix(event.Ref, "refs/tags/") {
+		return "path filters are not evaluated for tag pushes", true
+	}
+	if event.ChangedFiles == nil {
+		return "changed files are unknown, so path filters were not evaluated", true
+	}
+
+	if len(e.Paths) > 0 {
+		for _, file := range event.ChangedFiles {
+			if included, pattern := matchFilterPatterns(e.Paths, file); included {
+				return fmt.Sprintf("changed file '%s' matches paths pattern '%s'", file, pattern), true
+			}
+		}
+		return fmt.Sprintf("none of the %d changed file(s) match paths [%s]", len(event.ChangedFiles), strings.Join(e.Paths, ", ")), false
+	}
+
+	for _, file := range event.ChangedFiles {
+		if ignored, _ := matchFilterPatterns(e.PathsIgnore, file); !ignored {
+			return fmt.Sprintf("changed file '%s' is not in paths-ignore", file), true
+		}
+	}
+	return fmt.Sprintf("all %d changed file(s) match paths-ignore [%s]", len(event.ChangedFiles), strings.Join(e.PathsIgnore, ", ")), false
+}
+
+// matchRefFilters applies an include or ignore list of patterns to a branch or tag name
+func matchRefFilters(kind, name string, include, ignore StringList) (string, bool) {
+	switch {
+	case len(include) > 0:
+		included, pattern := matchFilterPatterns(include, name)
+		switch {
+		case included:
+			return fmt.Sprintf("%s '%s' matches pattern '%s'", kind, name, pattern), true
+		case pattern != "":
+			return fmt.Sprintf("%s '%s' is excluded by pattern '%s'", kind, name, pattern), false
+		default:
+			return fmt.Sprintf("%s '%s' does not match [%s]", kind, name, strings.Join(include, ", ")), false
+		}
+
+	case len(ignore) > 0:
+		if ignored, pattern := matchFilterPatterns(ignore, name); ignored {
+			return fmt.Sprintf("%s '%s' is ignored by pattern '%s'", kind, name, pattern), false
+		}
+		return fmt.Sprintf("%s '%s' is not ignored", kind, name), true
+
+	default:
+		return "", true
+	}
+}
+
+// matchFilterPatterns evaluates patterns in order; a later match overrides an earlier one,
+// and a pattern starting with ! excludes what it matches. It returns whether the value
+// ends up included and the pattern that decided it.
+func matchFilterPatterns(patterns []string, value string) (bool, string) {
+	included := false
+	decidedBy := ""
+	for _, pattern := range patterns {
+		negated := strings.HasPrefix(pattern, "!")
+		matcher, err := compileFilterPattern(pattern)
+		if err != nil {
+			continue // Rejected when the workflow was parsed
+		}
+		if matcher.MatchString(value) {
+			included = !negated
+			decidedBy = pattern
+		}
+	}
+	return included, decidedBy
+}
+
+// compileFilterPattern translates a branch, tag or path filter into a regular expression:
+// * matches any characters except /, ** matches any characters, **/ also matches no
+// directory at all, so docs/**/*.md matches docs/README.md, ? and + repeat the
+// preceding character zero-or-one and one-or-more times, [] is a character class,
+// and \ escapes the next character. A leading ! (negation) is ignored here.
+func compileFilterPattern(pattern string) (*regexp.Regexp, error) {
+	body := strings.TrimPrefix(pattern, "!")
+	if body == "" {
+		return nil, fmt.Errorf("empty filter pattern '%s'", pattern)
+	}
+
+	var expression strings.Builder
+	expression.WriteString("^")
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		switch c {
+		case '*':
+			if strings.HasPrefix(body[i:], "**/") {
+				expression.WriteString("(?:.*/)?")
+				i += 2
+			} else if i+1 < len(body) && body[i+1] == '*' {
+				expression.WriteString(".*")
+				i++
+			} else {
+				expression.WriteString("[^/]*")
+			}
+		case '?', '+':
+			if i == 0 {
+				return nil, fmt.Errorf("invalid filter pattern '%s': '%c' must follow a character", pattern, c)
+			}
+			expression.WriteByte(c)
+		case '[':
+			end := strings.IndexByte(body[i:], ']')
+			if end == -1 {
+				return nil, fmt.Errorf("invalid filter pattern '%s': unclosed '['", pattern)
+			}
+			expression.WriteString(body[i : i+end+1])
+			i += end
+		case '\\':
+			if i+1 < len(body) {
+				i++
+			}
+			expression.WriteString(regexp.QuoteMeta(string(body[i])))
+		default:
+			expression.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	expression.WriteString("$")
+
+	matcher, err := regexp.Compile(expression.String())
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter pattern '%s': %w", pattern, err)
+	}
+	return matcher, nil
+}
+
+func rejected(format string, args ...interface{}) TriggerResult {
+	return TriggerResult{Fires: false, Reasons: []string{fmt.Sprintf(format, args...)}}
+}
@@ -0,0 +1,114 @@
+package workflow
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// The patterns follow the filter pattern cheat sheet in GitHub's docs
+func TestMatchFilterPatterns(t *testing.T) {
+	tests := []struct {
+		name      string
+		patterns  []string
+		value     string
+		want      bool
+		decidedBy string
+	}{
+		{"literal", []string{"main"}, "main", true, "main"},
+		{"literal mismatch", []string{"main"}, "mainline", false, ""},
+		{"star stays in one directory", []string{"feature/*"}, "feature/my-branch", true, "feature/*"},
+		{"star does not cross /", []string{"feature/*"}, "feature/your/branch", false, ""},
+		{"double star crosses /", []string{"feature/**"}, "feature/your/branch", true, "feature/**"},
+		{"star prefix", []string{"*feature"}, "ver-10-feature", true, "*feature"},
+		{"star suffix", []string{"v2*"}, "v2.9", true, "v2*"},
+		{"character class and plus", []string{"v[12].[0-9]+.[0-9]+"}, "v1.10.1", true, "v[12].[0-9]+.[0-9]+"},
+		{"character class mismatch", []string{"v[12].[0-9]+.[0-9]+"}, "v3.0.0", false, ""},
+		{"dot is literal", []string{"v1.0"}, "v1x0", false, ""},
+		{"question mark makes the character optional", []string{"*.jsx?"}, "page.js", true, "*.jsx?"},
+		{"question mark also matches it", []string{"*.jsx?"}, "page.jsx", true, "*.jsx?"},
+		{"star does not match paths in directories", []string{"*"}, "docs/README.md", false, ""},
+		{"double star extension", []string{"**.js"}, "src/js/app.js", true, "**.js"},
+		{"double star directory", []string{"docs/**/*.md"}, "docs/mona/hello-world.md", true, "docs/**/*.md"},
+		{"double star directory matches no directory", []string{"docs/**/*.md"}, "docs/README.md", true, "docs/**/*.md"},
+		{"leading double star matches the root", []string{"**/README.md"}, "README.md", true, "**/README.md"},
+		{"leading double star matches nested", []string{"**/migrate-*.sql"}, "db/migrate-v1.sql", true, "**/migrate-*.sql"},
+		{"escaped special character", []string{`release\*`}, "release*", true, `release\*`},
+		{"escaped special character is literal", []string{`release\*`}, "release1", false, ""},
+		{"negation excludes a match", []string{"releases/**", "!releases/**-alpha"}, "releases/10-alpha", false, "!releases/**-alpha"},
+		{"negation leaves other matches", []string{"releases/**", "!releases/**-alpha"}, "releases/10", true, "releases/**"},
+		{"negation alone includes nothing", []string{"!docs/**"}, "src/main.go", false, ""},
+		{"last match wins", []string{"**", "!docs/**", "docs/keep.md"}, "docs/keep.md", true, "docs/keep.md"},
+		{"last match wins over negation", []string{"**", "!docs/**", "docs/keep.md"}, "docs/drop.md", false, "!docs/**"},
+		{"invalid patterns are skipped", []string{"[main", "main"}, "main", true, "main"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, decidedBy := matchFilterPatterns(tt.patterns, tt.value)
+			if got != tt.want || decidedBy != tt.decidedBy {
+				t.Errorf("matchFilterPatterns(%q, %q) = %v, %q; want %v, %q", tt.patterns, tt.value, got, decidedBy, tt.want, tt.decidedBy)
+			}
+		})
+	}
+}
+
+func TestCompileFilterPatternErrors(t *testing.T) {
+	for _, pattern := range []string{"", "!", "?main", "+main", "release-[0-9"} {
+		t.Run(pattern, func(t *testing.T) {
+			if _, err := compileFilterPattern(pattern); err == nil {
+				t.Errorf("compileFilterPattern(%q) succeeded, want an error", pattern)
+			}
+		})
+	}
+}
+
+func TestTriggersEvaluate(t *testing.T) {
+	tests := []struct {
+		name  string
+		on    string
+		event TriggerEvent
+		want  bool
+	}{
+		{"unlisted event", "push", TriggerEvent{Name: "pull_request", Action: "opened"}, false},
+		{"listed event", "[push, pull_request]", TriggerEvent{Name: "push", Ref: "refs/heads/main"}, true},
+
+		// pull_request runs for opened, synchronize and reopened unless types are listed
+		{"default pull_request type", "pull_request", TriggerEvent{Name: "pull_request", Action: "synchronize"}, true},
+		{"pull_request type outside the default", "pull_request", TriggerEvent{Name: "pull_request", Action: "closed"}, false},
+		{"pull_request_target default types", "pull_request_target", TriggerEvent{Name: "pull_request_target", Action: "labeled"}, false},
+		{"listed pull_request type", "pull_request:\n  types: [closed]", TriggerEvent{Name: "pull_request", Action: "closed"}, true},
+		{"listed types replace the default", "pull_request:\n  types: [closed]", TriggerEvent{Name: "pull_request", Action: "opened"}, false},
+		{"types without an activity", "release:\n  types: [published]", TriggerEvent{Name: "release"}, false},
+		{"events without types", "release", TriggerEvent{Name: "release", Action: "created"}, true},
+
+		{"branch filter", "push:\n  branches: [main, 'releases/**']", TriggerEvent{Name: "push", Ref: "refs/heads/releases/v1"}, true},
+		{"branch filter mismatch", "push:\n  branches: [main]", TriggerEvent{Name: "push", Ref: "refs/heads/dev"}, false},
+		{"branches-ignore", "push:\n  branches-ignore: ['dependabot/**']", TriggerEvent{Name: "push", Ref: "refs/heads/dependabot/npm"}, false},
+		{"only branch filters ignore tags", "push:\n  branches: [main]", TriggerEvent{Name: "push", Ref: "refs/tags/v1.0"}, false},
+		{"only tag filters ignore branches", "push:\n  tags: ['v*']", TriggerEvent{Name: "push", Ref: "refs/heads/main"}, false},
+		{"tag filter", "push:\n  tags: ['v*']", TriggerEvent{Name: "push", Ref: "refs/tags/v1.0"}, true},
+		{"pull_request matches the base branch", "pull_request:\n  branches: [main]", TriggerEvent{Name: "pull_request", Action: "opened", Ref: "refs/heads/feature", BaseRef: "main"}, true},
+
+		{"paths match one changed file", "push:\n  paths: ['src/**']", TriggerEvent{Name: "push", Ref: "refs/heads/main", ChangedFiles: []string{"README.md", "src/main.go"}}, true},
+		{"paths match no changed file", "push:\n  paths: ['src/**']", TriggerEvent{Name: "push", Ref: "refs/heads/main", ChangedFiles: []string{"README.md"}}, false},
+		{"paths with negation", "push:\n  paths: ['src/**', '!src/docs/**']", TriggerEvent{Name: "push", Ref: "refs/heads/main", ChangedFiles: []string{"src/docs/guide.md"}}, false},
+		{"paths-ignore with a file left", "push:\n  paths-ignore: ['docs/**']", TriggerEvent{Name: "push", Ref: "refs/heads/main", ChangedFiles: []string{"docs/a.md", "main.go"}}, true},
+		{"paths-ignore with every file ignored", "push:\n  paths-ignore: ['docs/**']", TriggerEvent{Name: "push", Ref: "refs/heads/main", ChangedFiles: []string{"docs/a.md"}}, false},
+		{"unknown changed files", "push:\n  paths: ['src/**']", TriggerEvent{Name: "push", Ref: "refs/heads/main"}, true},
+		{"paths are not evaluated for tags", "push:\n  paths: ['src/**']", TriggerEvent{Name: "push", Ref: "refs/tags/v1", ChangedFiles: []string{"README.md"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var triggers Triggers
+			if err := yaml.Unmarshal([]byte(tt.on), &triggers); err != nil {
+				t.Fatalf("failed to parse on: %v", err)
+			}
+			result := triggers.Evaluate(tt.event)
+			if result.Fires != tt.want {
+				t.Errorf("Evaluate(%+v).Fires = %v, want %v (%q)", tt.event, result.Fires, tt.want, result.Reasons)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package workflow
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Input types supported by workflow_dispatch
+const (
+	InputTypeString      = "string"
+	InputTypeBoolean     = "boolean"
+	InputTypeNumber      = "number"
+	InputTypeChoice      = "choice"
+	InputTypeEnvironment = "environment"
+)
+
+// InputDefinition declares one workflow_dispatch input
+type InputDefinition struct {
+	Description string   `yaml:"description,omitempty"`
+	Required    bool     `yaml:"required,omitempty"`
+	Default     *string  `yaml:"default,omitempty"` // nil when no default is declared
+	Type        string   `yaml:"type,omitempty"`    // Defaults to string
+	Options     []string `yaml:"options,omitempty"` // Allowed values of a choice input
+}
+
+// InputPrompt asks for the value of a required input that was not provided
+type InputPrompt func(name string, input InputDefinition) (string, error)
+
+// DispatchInputs returns the inputs declared under on.workflow_dispatch.inputs
+func (w *WorkflowDefinition) DispatchInputs() map[string]InputDefinition {
+	if dispatch := w.On.Events["workflow_dispatch"]; dispatch != nil && dispatch.Inputs != nil {
+		return dispatch.Inputs
+	}
+	return map[string]InputDefinition{}
+}
+
+// ResolveInputs checks provided values against the input definitions, applies defaults and
+// prompts for missing required inputs when prompt is set. The result holds typed values:
+// booleans for boolean inputs, numbers for number inputs and strings otherwise.
+func ResolveInputs(definitions map[string]InputDefinition, provided map[string]string, prompt InputPrompt) (map[string]interface{}, error) {
+	for name := range provided {
+		if _, declared := definitions[name]; !declared {
+			return nil, fmt.Errorf("unknown input '%s'; the workflow declares: %s", name, declaredInputs(definitions))
+		}
+	}
+
+	names := make([]string, 0, len(definitions))
+	for name := range definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	resolved := make(map[string]interface{}, len(definitions))
+	var missing []string
+	for _, name := range names {
+		input := definitions[name]
+
+		value, isSet := provided[name]
+		if !isSet && input.Default != nil {
+			value, isSet = *input.Default, true
+		}
+		if !isSet && input.Required {
+			if prompt == nil {
+				missing = append(missing, name)
+				continue
+			}
+			var err error
+			if value, err = prompt(name, input); err != nil {
+				return nil, fmt.Errorf("failed to read input '%s': %w", name, err)
+			}
+			isSet = true
+		}
+		if !isSet {
+			resolved[name] = input.zeroValue()
+			continue
+		}
+
+		typed, err := input.Parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("input '%s': %w", name, err)
+		}
+		resolved[name] = typed
+	}
+
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing required input(s): %s (pass them with --input name=value)", strings.Join(missing, ", "))
+	}
+	return resolved, nil
+}
+
+// Parse validates a value against the input's type and choice options and converts it
+func (i InputDefinition) Parse(value string) (interface{}, error) {
+	switch i.inputType() {
+	case InputTypeBoolean:
+		switch strings.ToLower(strings.TrimSpace(value)) {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+		return nil, fmt.Errorf("'%s' is not a boolean (expected true or false)", value)
+
+	case InputTypeNumber:
+		number, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return nil, fmt.Errorf("'%s' is not a number", value)
+		}
+		return number, nil
+
+	case InputTypeChoice:
+		for _, option := range i.Options {
+			if option == value {
+				return value, nil
+			}
+		}
+		return nil, fmt.Errorf("'%s' is not one of the options: %s", value, strings.Join(i.Options, ", "))
+
+	default:
+		return value, nil
+	}
+}
+
+// validate checks that the definition itself is usable
+func (i InputDefinition) validate() error {
+	switch i.inputType() {
+	case InputTypeString, InputTypeBoolean, InputTypeNumber, InputTypeEnvironment:
+	case InputTypeChoice:
+		if len(i.Options) == 0 {
+			return fmt.Errorf("choice inputs must list options")
+		}
+	default:
+		return fmt.Errorf("unsupported type '%s'", i.Type)
+	}
+
+	if i.Default != nil {
+		if _, err := i.Parse(*i.Default); err != nil {
+			return fmt.Errorf("invalid default: %w", err)
+		}
+	}
+	return nil
+}
+
+func (i InputDefinition) inputType() string {
+	if i.Type == "" {
+		return InputTypeString
+	}
+	return i.Type
+}
+
+// zeroValue is what an optional input without a default resolves to, as on GitHub
+func (i InputDefinition) zeroValue() interface{} {
+	switch i.inputType() {
+	case InputTypeBoolean:
+		return false
+	case InputTypeNumber:
+		return float64(0)
+	default:
+		return ""
+	}
+}
+
+func declaredInputs(definitions map[string]InputDefinition) string {
+	if len(definitions) == 0 {
+		return "no inputs"
+	}
+	names := make([]string, 0, len(definitions))
+	for name := range definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,218 @@
+package workflow
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func stringPtr(value string) *string {
+	return &value
+}
+
+func TestInputParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   InputDefinition
+		value   string
+		want    interface{}
+		wantErr bool
+	}{
+		{"string by default", InputDefinition{}, " any value ", " any value ", false},
+		{"string", InputDefinition{Type: InputTypeString}, "", "", false},
+		{"environment is a string", InputDefinition{Type: InputTypeEnvironment}, "staging", "staging", false},
+		{"boolean true", InputDefinition{Type: InputTypeBoolean}, "true", true, false},
+		{"boolean is case-insensitive", InputDefinition{Type: InputTypeBoolean}, " FALSE ", false, false},
+		{"boolean rejects yes", InputDefinition{Type: InputTypeBoolean}, "yes", nil, true},
+		{"boolean rejects empty", InputDefinition{Type: InputTypeBoolean}, "", nil, true},
+		{"integer number", InputDefinition{Type: InputTypeNumber}, "3", float64(3), false},
+		{"decimal number", InputDefinition{Type: InputTypeNumber}, " -1.5 ", -1.5, false},
+		{"number rejects text", InputDefinition{Type: InputTypeNumber}, "three", nil, true},
+		{"choice option", InputDefinition{Type: InputTypeChoice, Options: []string{"info", "debug"}}, "debug", "debug", false},
+		{"choice is case-sensitive", InputDefinition{Type: InputTypeChoice, Options: []string{"info", "debug"}}, "DEBUG", nil, true},
+		{"choice rejects other values", InputDefinition{Type: InputTypeChoice, Options: []string{"info"}}, "trace", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.input.Parse(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Parse(%q) = %v, want an error", tt.value, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q) returned error: %v", tt.value, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q) = %#v, want %#v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInputValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   InputDefinition
+		wantErr bool
+	}{
+		{"untyped", InputDefinition{}, false},
+		{"boolean with a default", InputDefinition{Type: InputTypeBoolean, Default: stringPtr("true")}, false},
+		{"choice with options", InputDefinition{Type: InputTypeChoice, Options: []string{"a"}, Default: stringPtr("a")}, false},
+		{"unsupported type", InputDefinition{Type: "list"}, true},
+		{"choice without options", InputDefinition{Type: InputTypeChoice}, true},
+		{"invalid boolean default", InputDefinition{Type: InputTypeBoolean, Default: stringPtr("maybe")}, true},
+		{"default outside the options", InputDefinition{Type: InputTypeChoice, Options: []string{"a"}, Default: stringPtr("b")}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.input.validate(); (err != nil) != tt.wantErr {
+				t.Errorf("validate() = %v, want error: %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResolveInputs(t *testing.T) {
+	definitions := map[string]InputDefinition{
+		"environment": {Type: InputTypeChoice, Options: []string{"staging", "production"}, Default: stringPtr("staging")},
+		"dry-run":     {Type: InputTypeBoolean, Default: stringPtr("true")},
+		"replicas":    {Type: InputTypeNumber},
+		"debug":       {Type: InputTypeBoolean},
+		"message":     {},
+	}
+
+	tests := []struct {
+		name     string
+		provided map[string]string
+		want     map[string]interface{}
+	}{
+		{
+			name: "defaults and zero values",
+			want: map[string]interface{}{
+				"environment": "staging",
+				"dry-run":     true,
+				"replicas":    float64(0),
+				"debug":       false,
+				"message":     "",
+			},
+		},
+		{
+			name:     "provided values are typed",
+			provided: map[string]string{"environment": "production", "dry-run": "false", "replicas": "3", "message": "hi"},
+			want: map[string]interface{}{
+				"environment": "production",
+				"dry-run":     false,
+				"replicas":    float64(3),
+				"debug":       false,
+				"message":     "hi",
+			},
+		},
+		{
+			name:     "empty string is a value",
+			provided: map[string]string{"message": ""},
+			want: map[string]interface{}{
+				"environment": "staging",
+				"dry-run":     true,
+				"replicas":    float64(0),
+				"debug":       false,
+				"message":     "",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveInputs(definitions, tt.provided, nil)
+			if err != nil {
+				t.Fatalf("ResolveInputs() returned error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ResolveInputs() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveInputsErrors(t *testing.T) {
+	definitions := map[string]InputDefinition{
+		"environment": {Type: InputTypeChoice, Options: []string{"staging", "production"}, Required: true},
+		"version":     {Required: true},
+		"replicas":    {Type: InputTypeNumber},
+	}
+
+	tests := []struct {
+		name     string
+		provided map[string]string
+		contains string
+	}{
+		{"unknown input", map[string]string{"environment": "staging", "version": "1", "region": "eu"}, "unknown input 'region'; the workflow declares: environment, replicas, version"},
+		{"missing required inputs", map[string]string{}, "missing required input(s): environment, version"},
+		{"invalid choice", map[string]string{"environment": "dev", "version": "1"}, "input 'environment': 'dev' is not one of the options: staging, production"},
+		{"invalid number", map[string]string{"environment": "staging", "version": "1", "replicas": "many"}, "input 'replicas': 'many' is not a number"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveInputs(definitions, tt.provided, nil)
+			if err == nil {
+				t.Fatalf("ResolveInputs() = %v, want an error", got)
+			}
+			if !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("ResolveInputs() error %q does not contain %q", err, tt.contains)
+			}
+		})
+	}
+
+	if _, err := ResolveInputs(nil, map[string]string{"x": "1"}, nil); err == nil || !strings.Contains(err.Error(), "no inputs") {
+		t.Errorf("ResolveInputs() without definitions returned %v, want an unknown input error", err)
+	}
+}
+
+func TestResolveInputsPrompt(t *testing.T) {
+	definitions := map[string]InputDefinition{
+		"version": {Required: true},
+		"debug":   {Type: InputTypeBoolean, Required: true, Default: stringPtr("false")},
+		"count":   {Type: InputTypeNumber, Required: true},
+	}
+
+	var asked []string
+	prompt := func(name string, input InputDefinition) (string, error) {
+		asked = append(asked, name)
+		if name == "count" {
+			return "2", nil
+		}
+		return "1.0", nil
+	}
+
+	got, err := ResolveInputs(definitions, nil, prompt)
+	if err != nil {
+		t.Fatalf("ResolveInputs() returned error: %v", err)
+	}
+	want := map[string]interface{}{"version": "1.0", "debug": false, "count": float64(2)}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ResolveInputs() = %#v, want %#v", got, want)
+	}
+	// Inputs with a default are never prompted for; the others are asked in name order
+	if wantAsked := []string{"count", "version"}; !reflect.DeepEqual(asked, wantAsked) {
+		t.Errorf("prompted for %q, want %q", asked, wantAsked)
+	}
+
+	failing := func(name string, input InputDefinition) (string, error) {
+		return "", fmt.Errorf("no terminal")
+	}
+	if _, err := ResolveInputs(definitions, nil, failing); err == nil || !strings.Contains(err.Error(), "failed to read input 'count'") {
+		t.Errorf("ResolveInputs() with a failing prompt returned %v", err)
+	}
+
+	invalid := func(name string, input InputDefinition) (string, error) {
+		return "lots", nil
+	}
+	if _, err := ResolveInputs(definitions, nil, invalid); err == nil || !strings.Contains(err.Error(), "is not a number") {
+		t.Errorf("ResolveInputs() with an invalid prompted value returned %v", err)
+	}
+}
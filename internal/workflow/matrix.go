@@ -0,0 +1,267 @@
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MaxMatrixCombinations mirrors GitHub's limit of 256 jobs per matrix
+const MaxMatrixCombinations = 256
+
+// StrategyDefinition represents the strategy block of a job
+type StrategyDefinition struct {
+	Matrix      MatrixDefinition `yaml:"matrix"`
+	FailFast    *bool            `yaml:"fail-fast,omitempty"`
+	MaxParallel int              `yaml:"max-parallel,omitempty"`
+}
+
+// IsFailFast reports whether remaining matrix jobs are cancelled after a failure (GitHub defaults to true)
+func (s *StrategyDefinition) IsFailFast() bool {
+	return s == nil || s.FailFast == nil || *s.FailFast
+}
+
+// MatrixDefinition holds the matrix axes along with include/exclude entries
+type MatrixDefinition struct {
+	Axes       map[string][]interface{}
+	AxisOrder  []string // Axis names in declaration order, used for job names
+	Include    []map[string]interface{}
+	Exclude    []map[string]interface{}
+	Expression string // Set when the whole matrix is a ${{ }} expression
+}
+
+// MatrixCombination is one expanded set of matrix values
+type MatrixCombination struct {
+	Keys   []string // Key order used when building display names
+	Values map[string]interface{}
+}
+
+// UnmarshalYAML implements custom YAML unmarshaling that keeps axis order
+func (m *MatrixDefinition) UnmarshalYAML(value *yaml.Node) error {
+	switch value.Kind {
+	case yaml.ScalarNode:
+		// Handle matrix: ${{ fromJSON(...) }}
+		return value.Decode(&m.Expression)
+
+	case yaml.MappingNode:
+		m.Axes = make(map[string][]interface{})
+		for i := 0; i+1 < len(value.Content); i += 2 {
+			key := value.Content[i].Value
+			node := value.Content[i+1]
+
+			switch key {
+			case "include":
+				if err := node.Decode(&m.Include); err != nil {
+					return fmt.Errorf("matrix include must be a list of objects: %w", err)
+				}
+			case "exclude":
+				if err := node.Decode(&m.Exclude); err != nil {
+					return fmt.Errorf("matrix exclude must be a list of objects: %w", err)
+				}
+			default:
+				var values []interface{}
+				if err := node.Decode(&values); err != nil {
+					return fmt.Errorf("matrix axis %s must be a list: %w", key, err)
+				}
+				m.Axes[key] = values
+				m.AxisOrder = append(m.AxisOrder, key)
+			}
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("matrix must be a mapping or an expression")
+	}
+}
+
+// NewMatrixDefinition builds a matrix from the value of a matrix expression such as
+// ${{ fromJSON(needs.setup.outputs.matrix) }}, which must evaluate to an object
+func NewMatrixDefinition(value interface{}) (*MatrixDefinition, error) {
+	if _, isObject := value.(map[string]interface{}); !isObject {
+		return nil, fmt.Errorf("matrix expression must evaluate to an object")
+	}
+
+	// JSON is valid YAML, so the regular parser handles axes, include and exclude
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid matrix value: %w", err)
+	}
+
+	var matrix MatrixDefinition
+	if err := yaml.Unmarshal(data, &matrix); err != nil {
+		return nil, err
+	}
+	return &matrix, nil
+}
+
+// IsEmpty reports whether the matrix defines no combinations at all
+func (m *MatrixDefinition) IsEmpty() bool {
+	return len(m.AxisOrder) == 0 && len(m.Include) == 0 && m.Expression == ""
+}
+
+// Expand computes the matrix combinations using GitHub's rules:
+// the cartesian product of all axes, minus exclude matches, plus include entries
+func (m *MatrixDefinition) Expand() ([]MatrixCombination, error) {
+	if m.Expression != "" {
+		return nil, fmt.Errorf("matrix expression %s must be evaluated before expansion", m.Expression)
+	}
+
+	// 1. Cartesian product of the axes
+	var combinations []MatrixCombination
+	if len(m.AxisOrder) > 0 {
+		combinations = []MatrixCombination{{Values: map[string]interface{}{}}}
+		for _, axis := range m.AxisOrder {
+			var next []MatrixCombination
+			for _, combination := range combinations {
+				for _, value := range m.Axes[axis] {
+					next = append(next, combination.with(axis, value))
+				}
+			}
+			combinations = next
+		}
+	}
+
+	// 2. Exclude removes every combination matching all keys of an entry
+	if len(m.Exclude) > 0 {
+		var kept []MatrixCombination
+		for _, combination := range combinations {
+			excluded := false
+			for _, exclude := range m.Exclude {
+				if combination.matches(exclude) {
+					excluded = true
+					break
+				}
+			}
+			if !excluded {
+				kept = append(kept, combination)
+			}
+		}
+		combinations = kept
+	}
+
+	// 3. Include extends every combination whose original values it does not overwrite,
+	// or becomes a new combination when it cannot extend any of them
+	originalCount := len(combinations)
+	for _, include := range m.Include {
+		extended := false
+		for i := 0; i < originalCount; i++ {
+			if !m.canExtend(combinations[i], include) {
+				continue
+			}
+			for _, key := range m.includeKeys(include) {
+				combinations[i] = combinations[i].with(key, include[key])
+			}
+			extended = true
+		}
+
+		if !extended {
+			combination := MatrixCombination{Values: map[string]interface{}{}}
+			for _, key := range m.includeKeys(include) {
+				combination = combination.with(key, include[key])
+			}
+			combinations = append(combinations, combination)
+		}
+	}
+
+	if len(combinations) > MaxMatrixCombinations {
+		return nil, fmt.Errorf("matrix produces %d combinations, exceeding the limit of %d", len(combinations), MaxMatrixCombinations)
+	}
+
+	return combinations, nil
+}
+
+// includeKeys orders an include entry's keys with matrix axes first, so the
+// job names of added combinations list values in the same order as the others
+func (m *MatrixDefinition) includeKeys(include map[string]interface{}) []string {
+	var keys []string
+	for _, axis := range m.AxisOrder {
+		if _, exists := include[axis]; exists {
+			keys = append(keys, axis)
+		}
+	}
+	for _, key := range sortedMapKeys(include) {
+		if _, isAxis := m.Axes[key]; !isAxis {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// canExtend reports whether an include entry agrees with every original axis value of a combination
+func (m *MatrixDefinition) canExtend(combination MatrixCombination, include map[string]interface{}) bool {
+	for key, value := range include {
+		if _, isAxis := m.Axes[key]; !isAxis {
+			continue
+		}
+		if !reflect.DeepEqual(combination.Values[key], value) {
+			return false
+		}
+	}
+	return true
+}
+
+// with returns a copy of the combination with key set to value
+func (mc MatrixCombination) with(key string, value interface{}) MatrixCombination {
+	values := make(map[string]interface{}, len(mc.Values)+1)
+	for k, v := range mc.Values {
+		values[k] = v
+	}
+
+	keys := mc.Keys
+	if _, exists := mc.Values[key]; !exists {
+		keys = append(append([]string{}, mc.Keys...), key)
+	}
+	values[key] = value
+
+	return MatrixCombination{Keys: keys, Values: values}
+}
+
+// matches reports whether every key/value pair in entry is present in the combination
+func (mc MatrixCombination) matches(entry map[string]interface{}) bool {
+	for key, value := range entry {
+		if !reflect.DeepEqual(mc.Values[key], value) {
+			return false
+		}
+	}
+	return true
+}
+
+// Name builds the GitHub-style display suffix, e.g. "18, ubuntu-latest"
+func (mc MatrixCombination) Name() string {
+	parts := make([]string, 0, len(mc.Keys))
+	for _, key := range mc.Keys {
+		parts = append(parts, FormatMatrixValue(mc.Values[key]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// FormatMatrixValue renders a matrix value as GitHub shows it in job names
+func FormatMatrixValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case map[string]interface{}, []interface{}:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return string(data)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+func sortedMapKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,290 @@
+package workflow
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func parseMatrix(t *testing.T, source string) *MatrixDefinition {
+	t.Helper()
+	var matrix MatrixDefinition
+	if err := yaml.Unmarshal([]byte(source), &matrix); err != nil {
+		t.Fatalf("failed to parse matrix: %v", err)
+	}
+	return &matrix
+}
+
+// The cases follow the examples of "Running variations of jobs in a workflow" in GitHub's docs
+func TestMatrixExpand(t *testing.T) {
+	tests := []struct {
+		name   string
+		matrix string
+		want   []map[string]interface{}
+		names  []string
+	}{
+		{
+			name: "single axis",
+			matrix: `
+version: [10, 12, 14]`,
+			want:  []map[string]interface{}{{"version": 10}, {"version": 12}, {"version": 14}},
+			names: []string{"10", "12", "14"},
+		},
+		{
+			name: "cartesian product in declaration order",
+			matrix: `
+os: [ubuntu-22.04, ubuntu-20.04]
+version: [10, 12, 14]`,
+			want: []map[string]interface{}{
+				{"os": "ubuntu-22.04", "version": 10},
+				{"os": "ubuntu-22.04", "version": 12},
+				{"os": "ubuntu-22.04", "version": 14},
+				{"os": "ubuntu-20.04", "version": 10},
+				{"os": "ubuntu-20.04", "version": 12},
+				{"os": "ubuntu-20.04", "version": 14},
+			},
+			names: []string{
+				"ubuntu-22.04, 10", "ubuntu-22.04, 12", "ubuntu-22.04, 14",
+				"ubuntu-20.04, 10", "ubuntu-20.04, 12", "ubuntu-20.04, 14",
+			},
+		},
+		{
+			name: "include extends only on original axis values, added values can be overwritten",
+			matrix: `
+fruit: [apple, pear]
+animal: [cat, dog]
+include:
+  - color: green
+  - color: pink
+    animal: cat
+  - fruit: apple
+    shape: circle
+  - fruit: banana
+  - fruit: banana
+    animal: cat`,
+			want: []map[string]interface{}{
+				{"fruit": "apple", "animal": "cat", "color": "pink", "shape": "circle"},
+				{"fruit": "apple", "animal": "dog", "color": "green", "shape": "circle"},
+				{"fruit": "pear", "animal": "cat", "color": "pink"},
+				{"fruit": "pear", "animal": "dog", "color": "green"},
+				{"fruit": "banana"},
+				{"fruit": "banana", "animal": "cat"},
+			},
+			names: []string{
+				"apple, cat, pink, circle",
+				"apple, dog, green, circle",
+				"pear, cat, pink",
+				"pear, dog, green",
+				"banana",
+				"banana, cat",
+			},
+		},
+		{
+			name: "include adds a key to one combination",
+			matrix: `
+os: [windows-latest, ubuntu-latest]
+node: [14, 16]
+include:
+  - os: windows-latest
+    node: 16
+    npm: 6`,
+			want: []map[string]interface{}{
+				{"os": "windows-latest", "node": 14},
+				{"os": "windows-latest", "node": 16, "npm": 6},
+				{"os": "ubuntu-latest", "node": 14},
+				{"os": "ubuntu-latest", "node": 16},
+			},
+		},
+		{
+			name: "include that changes an original value is appended",
+			matrix: `
+os: [macos-latest, windows-latest]
+node: [16]
+include:
+  - os: ubuntu-latest
+    node: 16
+    experimental: true`,
+			want: []map[string]interface{}{
+				{"os": "macos-latest", "node": 16},
+				{"os": "windows-latest", "node": 16},
+				{"os": "ubuntu-latest", "node": 16, "experimental": true},
+			},
+			names: []string{"macos-latest, 16", "windows-latest, 16", "ubuntu-latest, 16, true"},
+		},
+		{
+			name: "include only",
+			matrix: `
+include:
+  - site: production
+    datacenter: site-a
+  - site: staging
+    datacenter: site-b`,
+			want: []map[string]interface{}{
+				{"site": "production", "datacenter": "site-a"},
+				{"site": "staging", "datacenter": "site-b"},
+			},
+			names: []string{"site-a, production", "site-b, staging"},
+		},
+		{
+			name: "exclude removes partial and full matches",
+			matrix: `
+os: [macos-latest, windows-latest]
+version: [12, 14, 16]
+environment: [staging, production]
+exclude:
+  - os: macos-latest
+    version: 12
+    environment: production
+  - os: windows-latest
+    version: 16`,
+			want: []map[string]interface{}{
+				{"os": "macos-latest", "version": 12, "environment": "staging"},
+				{"os": "macos-latest", "version": 14, "environment": "staging"},
+				{"os": "macos-latest", "version": 14, "environment": "production"},
+				{"os": "macos-latest", "version": 16, "environment": "staging"},
+				{"os": "macos-latest", "version": 16, "environment": "production"},
+				{"os": "windows-latest", "version": 12, "environment": "staging"},
+				{"os": "windows-latest", "version": 12, "environment": "production"},
+				{"os": "windows-latest", "version": 14, "environment": "staging"},
+				{"os": "windows-latest", "version": 14, "environment": "production"},
+			},
+		},
+		{
+			name: "exclude runs before include",
+			matrix: `
+os: [ubuntu, windows]
+exclude:
+  - os: windows
+include:
+  - os: windows
+    shell: pwsh`,
+			want: []map[string]interface{}{
+				{"os": "ubuntu"},
+				{"os": "windows", "shell": "pwsh"},
+			},
+		},
+		{
+			name: "object values",
+			matrix: `
+node:
+  - version: 14
+  - version: 20
+    env: {NODE_OPTIONS: --openssl-legacy-provider}
+include:
+  - node: {version: 14}
+    os: ubuntu`,
+			want: []map[string]interface{}{
+				{"node": map[string]interface{}{"version": 14}, "os": "ubuntu"},
+				{"node": map[string]interface{}{"version": 20, "env": map[string]interface{}{"NODE_OPTIONS": "--openssl-legacy-provider"}}},
+			},
+			names: []string{
+				`{"version":14}, ubuntu`,
+				`{"env":{"NODE_OPTIONS":"--openssl-legacy-provider"},"version":20}`,
+			},
+		},
+		{
+			name: "everything excluded",
+			matrix: `
+os: [ubuntu]
+exclude:
+  - os: ubuntu`,
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			combinations, err := parseMatrix(t, tt.matrix).Expand()
+			if err != nil {
+				t.Fatalf("Expand() returned error: %v", err)
+			}
+
+			var got []map[string]interface{}
+			var names []string
+			for _, combination := range combinations {
+				got = append(got, combination.Values)
+				names = append(names, combination.Name())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expand() =\n%v\nwant\n%v", got, tt.want)
+			}
+			if tt.names != nil && !reflect.DeepEqual(names, tt.names) {
+				t.Errorf("names = %q, want %q", names, tt.names)
+			}
+		})
+	}
+}
+
+func TestMatrixExpandLimit(t *testing.T) {
+	axis := func(size int) string {
+		values := make([]string, size)
+		for i := range values {
+			values[i] = fmt.Sprint(i)
+		}
+		return "[" + strings.Join(values, ", ") + "]"
+	}
+
+	tests := []struct {
+		name    string
+		matrix  string
+		want    int
+		wantErr bool
+	}{
+		{"at the limit", "a: " + axis(16) + "\nb: " + axis(16), 256, false},
+		{"over the limit", "a: " + axis(16) + "\nb: " + axis(17), 0, true},
+		{"exclude brings it under the limit", "a: " + axis(16) + "\nb: " + axis(17) + "\nexclude:\n  - b: 0", 256, false},
+		{"include pushes it over the limit", "a: " + axis(16) + "\nb: " + axis(16) + "\ninclude:\n  - a: extra", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			combinations, err := parseMatrix(t, tt.matrix).Expand()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expand() produced %d combinations, want an error", len(combinations))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expand() returned error: %v", err)
+			}
+			if len(combinations) != tt.want {
+				t.Errorf("Expand() produced %d combinations, want %d", len(combinations), tt.want)
+			}
+		})
+	}
+}
+
+func TestMatrixExpandExpression(t *testing.T) {
+	matrix := parseMatrix(t, "${{ fromJSON(needs.setup.outputs.matrix) }}")
+	if _, err := matrix.Expand(); err == nil {
+		t.Fatalf("Expand() of an unevaluated expression succeeded")
+	}
+
+	evaluated, err := NewMatrixDefinition(map[string]interface{}{
+		"os":      []interface{}{"ubuntu", "macos"},
+		"include": []interface{}{map[string]interface{}{"os": "macos", "arch": "arm64"}},
+	})
+	if err != nil {
+		t.Fatalf("NewMatrixDefinition() returned error: %v", err)
+	}
+	combinations, err := evaluated.Expand()
+	if err != nil {
+		t.Fatalf("Expand() returned error: %v", err)
+	}
+	want := []map[string]interface{}{{"os": "ubuntu"}, {"os": "macos", "arch": "arm64"}}
+	var got []map[string]interface{}
+	for _, combination := range combinations {
+		got = append(got, combination.Values)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expand() = %v, want %v", got, want)
+	}
+
+	if _, err := NewMatrixDefinition([]interface{}{"ubuntu"}); err == nil {
+		t.Errorf("NewMatrixDefinition() of a list succeeded")
+	}
+}
@@ -22,7 +22,12 @@ func (p *Parser) ParseFile(filename string) (*WorkflowDefinition, error) {
 		return nil, fmt.Errorf("failed to read workflow file %s: %w", filename, err)
 	}
 
-	return p.Parse(data)
+	workflow, err := p.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	workflow.File = filename
+	return workflow, nil
 }
 
 // Parse parses workflow YAML data
@@ -42,5 +47,14 @@ func (p *Parser) Parse(data []byte) (*WorkflowDefinition, error) {
 		return nil, fmt.Errorf("workflow must contain at least one job")
 	}
 
+	for jobID, job := range workflow.Jobs {
+		if err := job.ValidateStepIDs(); err != nil {
+			return nil, fmt.Errorf("job %s: %w", jobID, err)
+		}
+		if err := job.validateCallJob(); err != nil {
+			return nil, fmt.Errorf("job %s: %w", jobID, err)
+		}
+	}
+
 	return &workflow, nil
 }
@@ -0,0 +1,139 @@
+package workflow
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SecretsInherit is the value of a job's secrets: that passes every secret of the caller
+const SecretsInherit = "inherit"
+
+// JobSecrets are the secrets a job passes to the reusable workflow it calls, written
+// either as secrets: inherit or as a mapping of secret names to expressions
+type JobSecrets struct {
+	Inherit bool
+	Values  map[string]string
+}
+
+// UnmarshalYAML implements custom YAML unmarshaling for a job's secrets field
+func (js *JobSecrets) UnmarshalYAML(value *yaml.Node) error {
+	switch value.Kind {
+	case yaml.ScalarNode:
+		if value.Value != SecretsInherit {
+			return fmt.Errorf("secrets must be 'inherit' or a mapping, got '%s'", value.Value)
+		}
+		js.Inherit = true
+		return nil
+
+	case yaml.MappingNode:
+		return value.Decode(&js.Values)
+
+	default:
+		return fmt.Errorf("secrets must be 'inherit' or a mapping of secret names to values")
+	}
+}
+
+// WorkflowCallOutput declares an output of a reusable workflow, usually mapped from
+// the outputs of one of its jobs
+type WorkflowCallOutput struct {
+	Description string `yaml:"description,omitempty"`
+	Value       string `yaml:"value"` // e.g. ${{ jobs.build.outputs.version }}
+}
+
+// WorkflowCallSecret declares a secret a reusable workflow accepts
+type WorkflowCallSecret struct {
+	Description string `yaml:"description,omitempty"`
+	Required    bool   `yaml:"required,omitempty"`
+}
+
+// WorkflowCall returns the on.workflow_call trigger of a reusable workflow
+func (w *WorkflowDefinition) WorkflowCall() (*EventTrigger, bool) {
+	trigger, exists := w.On.Events["workflow_call"]
+	if !exists {
+		return nil, false
+	}
+	if trigger == nil {
+		trigger = &EventTrigger{}
+	}
+	return trigger, true
+}
+
+// IsLocalWorkflowRef reports whether a job-level uses: names a workflow in this repository
+func IsLocalWorkflowRef(uses string) bool {
+	return strings.HasPrefix(uses, "./")
+}
+
+// ValidateCall checks a job's with: and secrets: against what the called workflow declares
+func (j *JobDefinition) ValidateCall(call *EventTrigger) error {
+	for name := range j.With {
+		if _, declared := call.Inputs[name]; !declared {
+			return fmt.Errorf("unknown input '%s'; the called workflow declares: %s", name, declaredInputs(call.Inputs))
+		}
+	}
+
+	var missing []string
+	for name, input := range call.Inputs {
+		if _, provided := j.With[name]; !provided && input.Required && input.Default == nil {
+			missing = append(missing, name)
+		}
+	}
+	sort.Strings(missing)
+	if len(missing) > 0 {
+		return fmt.Errorf("missing required input(s): %s", strings.Join(missing, ", "))
+	}
+
+	if j.Secrets == nil || !j.Secrets.Inherit {
+		var passed map[string]string
+		if j.Secrets != nil {
+			passed = j.Secrets.Values
+		}
+		for name := range passed {
+			if _, declared := call.Secrets[name]; !declared {
+				return fmt.Errorf("secret '%s' is not declared by the called workflow", name)
+			}
+		}
+		for name, secret := range call.Secrets {
+			if _, provided := passed[name]; !provided && secret.Required {
+				missing = append(missing, name)
+			}
+		}
+		sort.Strings(missing)
+		if len(missing) > 0 {
+			return fmt.Errorf("missing required secret(s): %s", strings.Join(missing, ", "))
+		}
+	}
+
+	return nil
+}
+
+// validateCallJob rejects keys that cannot be combined with a job-level uses:
+func (j *JobDefinition) validateCallJob() error {
+	if j.Uses == "" {
+		if j.Secrets != nil {
+			return fmt.Errorf("secrets can only be passed to a reusable workflow called with uses")
+		}
+		return nil
+	}
+	if len(j.Steps) > 0 || j.RunsOn != "" {
+		return fmt.Errorf("a job that calls a reusable workflow cannot have runs-on or steps")
+	}
+	if strings.Contains(j.Uses, "@") && IsLocalWorkflowRef(j.Uses) {
+		return fmt.Errorf("local reusable workflow '%s' cannot have a version; it is always read from the working tree", j.Uses)
+	}
+	return nil
+}
+
+// validateCallInputs checks that workflow_call inputs use the types GitHub allows there
+func (e *EventTrigger) validateCallInputs() error {
+	for name, input := range e.Inputs {
+		switch input.inputType() {
+		case InputTypeString, InputTypeBoolean, InputTypeNumber:
+		default:
+			return fmt.Errorf("input '%s': workflow_call inputs must be string, boolean or number, not %s", name, input.Type)
+		}
+	}
+	return nil
+}
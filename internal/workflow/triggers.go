@@ -0,0 +1,173 @@
+package workflow
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// StringList holds a value written either as a single string or as a list of strings
+type StringList []string
+
+// UnmarshalYAML implements custom YAML unmarshaling for string-or-list fields
+func (sl *StringList) UnmarshalYAML(value *yaml.Node) error {
+	switch value.Kind {
+	case yaml.ScalarNode:
+		var single string
+		if err := value.Decode(&single); err != nil {
+			return err
+		}
+		*sl = StringList{single}
+		return nil
+
+	case yaml.SequenceNode:
+		var multiple []string
+		if err := value.Decode(&multiple); err != nil {
+			return err
+		}
+		*sl = StringList(multiple)
+		return nil
+
+	default:
+		return fmt.Errorf("expected a string or a list of strings")
+	}
+}
+
+// Triggers is the parsed on: section of a workflow. It accepts the string form
+// (on: push), the list form (on: [push, pull_request]) and the map form with filters.
+type Triggers struct {
+	Events   map[string]*EventTrigger // Every listed event, including schedule and workflow_dispatch
+	Order    []string                 // Event names in declaration order
+	Schedule []ScheduleTrigger        // Cron schedules from on.schedule
+}
+
+// EventTrigger holds the filters configured for one event
+type EventTrigger struct {
+	Branches       StringList `yaml:"branches,omitempty"`
+	BranchesIgnore StringList `yaml:"branches-ignore,omitempty"`
+	Tags           StringList `yaml:"tags,omitempty"`
+	TagsIgnore     StringList `yaml:"tags-ignore,omitempty"`
+	Paths          StringList `yaml:"paths,omitempty"`
+	PathsIgnore    StringList `yaml:"paths-ignore,omitempty"`
+	Types          StringList `yaml:"types,omitempty"` // Activity types, e.g. opened or published
+
+	// Inputs declared by workflow_dispatch or workflow_call
+	Inputs map[string]InputDefinition `yaml:"inputs,omitempty"`
+
+	// Outputs and secrets declared by workflow_call
+	Outputs map[string]WorkflowCallOutput `yaml:"outputs,omitempty"`
+	Secrets map[string]WorkflowCallSecret `yaml:"secrets,omitempty"`
+}
+
+// ScheduleTrigger is one entry of on.schedule
+type ScheduleTrigger struct {
+	Cron string `yaml:"cron"`
+}
+
+// UnmarshalYAML implements custom YAML unmarshaling for the on: section
+func (t *Triggers) UnmarshalYAML(value *yaml.Node) error {
+	t.Events = make(map[string]*EventTrigger)
+
+	switch value.Kind {
+	case yaml.ScalarNode:
+		// on: push
+		t.add(value.Value, &EventTrigger{})
+		return nil
+
+	case yaml.SequenceNode:
+		// on: [push, pull_request]
+		var names []string
+		if err := value.Decode(&names); err != nil {
+			return fmt.Errorf("on must list event names: %w", err)
+		}
+		for _, name := range names {
+			t.add(name, &EventTrigger{})
+		}
+		return nil
+
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(value.Content); i += 2 {
+			name := value.Content[i].Value
+			node := value.Content[i+1]
+
+			if name == "schedule" {
+				if err := node.Decode(&t.Schedule); err != nil {
+					return fmt.Errorf("on.schedule must be a list of cron entries: %w", err)
+				}
+				for _, schedule := range t.Schedule {
+					if _, err := ParseCron(schedule.Cron); err != nil {
+						return fmt.Errorf("on.schedule: %w", err)
+					}
+				}
+				t.add(name, &EventTrigger{})
+				continue
+			}
+
+			trigger := &EventTrigger{}
+			// An event with no configuration (push: or push: {}) is decoded as null
+			if node.Kind != yaml.ScalarNode || node.Tag != "!!null" {
+				if err := node.Decode(trigger); err != nil {
+					return fmt.Errorf("on.%s: %w", name, err)
+				}
+			}
+			if err := trigger.validate(); err != nil {
+				return fmt.Errorf("on.%s: %w", name, err)
+			}
+			if name == "workflow_call" {
+				if err := trigger.validateCallInputs(); err != nil {
+					return fmt.Errorf("on.%s: %w", name, err)
+				}
+			}
+			t.add(name, trigger)
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("on must be an event name, a list of event names or a mapping")
+	}
+}
+
+// Has reports whether the workflow is triggered by the named event
+func (t *Triggers) Has(event string) bool {
+	_, exists := t.Events[event]
+	return exists
+}
+
+func (t *Triggers) add(name string, trigger *EventTrigger) {
+	if _, exists := t.Events[name]; !exists {
+		t.Order = append(t.Order, name)
+	}
+	t.Events[name] = trigger
+}
+
+// validate rejects filter combinations GitHub refuses and patterns that cannot be matched
+func (e *EventTrigger) validate() error {
+	exclusive := []struct {
+		include, ignore StringList
+		name            string
+	}{
+		{e.Branches, e.BranchesIgnore, "branches"},
+		{e.Tags, e.TagsIgnore, "tags"},
+		{e.Paths, e.PathsIgnore, "paths"},
+	}
+	for _, filter := range exclusive {
+		if len(filter.include) > 0 && len(filter.ignore) > 0 {
+			return fmt.Errorf("%s and %s-ignore cannot be used together; use ! patterns in %s instead", filter.name, filter.name, filter.name)
+		}
+	}
+
+	for _, patterns := range []StringList{e.Branches, e.BranchesIgnore, e.Tags, e.TagsIgnore, e.Paths, e.PathsIgnore} {
+		for _, pattern := range patterns {
+			if _, err := compileFilterPattern(pattern); err != nil {
+				return err
+			}
+		}
+	}
+
+	for name, input := range e.Inputs {
+		if err := input.validate(); err != nil {
+			return fmt.Errorf("input '%s': %w", name, err)
+		}
+	}
+	return nil
+}
@@ -40,30 +40,102 @@ func (jn JobNeeds) ToSlice() []string {
 	return []string(jn)
 }
 
+// JobEnvironment is the deployment environment a job targets, written either as
+// environment: production or as environment: {name: production, url: ...}
+type JobEnvironment struct {
+	Name string `yaml:"name"`
+	URL  string `yaml:"url,omitempty"`
+}
+
+// UnmarshalYAML implements custom YAML unmarshaling for the environment field
+func (je *JobEnvironment) UnmarshalYAML(value *yaml.Node) error {
+	switch value.Kind {
+	case yaml.ScalarNode:
+		return value.Decode(&je.Name)
+
+	case yaml.MappingNode:
+		type plain JobEnvironment // Avoids recursing into this method
+		return value.Decode((*plain)(je))
+
+	default:
+		return fmt.Errorf("environment must be a name or a mapping with name and url")
+	}
+}
+
 // WorkflowDefinition represents the parsed workflow YAML
 type WorkflowDefinition struct {
+	File string                   `yaml:"-"` // Path the workflow was loaded from, used in error messages
 	Name string                   `yaml:"name"`
-	On   map[string]interface{}   `yaml:"on"`
+	On   Triggers                 `yaml:"on"`
 	Env  map[string]string        `yaml:"env,omitempty"`
 	Jobs map[string]JobDefinition `yaml:"jobs"`
 }
 
 // JobDefinition represents a single job in the workflow
 type JobDefinition struct {
-	RunsOn string                 `yaml:"runs-on"`
-	Needs  JobNeeds               `yaml:"needs"`
-	With   map[string]interface{} `yaml:"with,omitempty"` // Action inputs
-	Env    map[string]string      `yaml:"env,omitempty"`
-	Steps  []StepDefinition       `yaml:"steps"`
+	RunsOn   string                 `yaml:"runs-on"`
+	Needs    JobNeeds               `yaml:"needs"`
+	If       string                 `yaml:"if,omitempty"` // Condition evaluated before the job starts
+	Strategy *StrategyDefinition    `yaml:"strategy,omitempty"`
+	With     map[string]interface{} `yaml:"with,omitempty"` // Action inputs
+	Env      map[string]string      `yaml:"env,omitempty"`
+	Outputs  map[string]string      `yaml:"outputs,omitempty"` // Values exposed to dependent jobs as needs.<id>.outputs
+	Steps    []StepDefinition       `yaml:"steps"`
+
+	// Uses calls a reusable workflow, e.g. ./.github/workflows/build.yml, with the
+	// job's with: as its inputs and secrets: as its secrets
+	Uses    string      `yaml:"uses,omitempty"`
+	Secrets *JobSecrets `yaml:"secrets,omitempty"`
+
+	// Environment selects the per-environment overrides of the vars context
+	Environment *JobEnvironment `yaml:"environment,omitempty"`
 }
 
 // StepDefinition represents a single step in a job
 type StepDefinition struct {
+	ID   string                 `yaml:"id,omitempty"` // Used to reference the step's outputs
 	Name string                 `yaml:"name"`
+	If   string                 `yaml:"if,omitempty"` // Condition evaluated before the step runs
 	Run  string                 `yaml:"run,omitempty"`
 	Uses string                 `yaml:"uses,omitempty"`
 	With map[string]interface{} `yaml:"with,omitempty"` // Action inputs
 	Env  map[string]string      `yaml:"env,omitempty"`  // Environment variables
+
+	// WorkingDirectory is where run: executes, relative to the workspace unless absolute
+	WorkingDirectory string `yaml:"working-directory,omitempty"`
+
+	// ContinueOnError is a boolean or an expression; when true a failing step does not fail the job
+	ContinueOnError string `yaml:"continue-on-error,omitempty"`
+}
+
+// ValidateStepIDs checks that step ids are well formed and unique within the job
+func (j *JobDefinition) ValidateStepIDs() error {
+	seen := make(map[string]bool)
+	for _, step := range j.Steps {
+		if step.ID == "" {
+			continue
+		}
+		if !isValidStepID(step.ID) {
+			return fmt.Errorf("invalid step id '%s': ids must start with a letter or '_' and contain only alphanumeric characters, '-' or '_'", step.ID)
+		}
+		if seen[step.ID] {
+			return fmt.Errorf("step id '%s' is used more than once", step.ID)
+		}
+		seen[step.ID] = true
+	}
+	return nil
+}
+
+func isValidStepID(id string) bool {
+	for i, r := range id {
+		switch {
+		case r == '_', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		case i > 0 && (r == '-' || r >= '0' && r <= '9'):
+		default:
+			return false
+		}
+	}
+	return true
 }
 
 // BuildExecutionPlan resolves job dependencies and returns execution order